@@ -1,16 +1,31 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/fredrikmwold/npm-tui/internal/commands"
 	"github.com/fredrikmwold/npm-tui/internal/ui"
 )
 
 func main() {
-	app := ui.New()
+	cfg, err := commands.LoadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "config: %v\n", err)
+		os.Exit(1)
+	}
+	if len(os.Args) > 1 && os.Args[1] == "license-report" {
+		os.Exit(licenseReport(cfg, os.Args[2:]))
+	}
+	app, err := ui.New(cfg)
+	if err != nil {
+		path, _ := commands.ConfigPath()
+		fmt.Fprintf(os.Stderr, "config: %s: %v\n", path, err)
+		os.Exit(1)
+	}
 	// Do not enable Bubble Tea mouse reporting here because when the program
 	// enables mouse reporting the terminal forwards mouse events to the
 	// application which in many terminals disables clickable OSC8 hyperlinks.
@@ -23,3 +38,53 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// licenseReport writes the licenses of all installed packages in the current
+// project as CSV or JSON and returns the exit code: 1 on errors or license
+// violations, 2 on bad flags.
+func licenseReport(cfg commands.Config, args []string) int {
+	fs := flag.NewFlagSet("license-report", flag.ContinueOnError)
+	format := fs.String("format", "csv", "output format: csv or json")
+	out := fs.String("o", "", "write to file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	cwd, _ := os.Getwd()
+	entries, err := commands.BuildLicenseReport(cwd, cfg.License)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "license-report: %v\n", err)
+		return 1
+	}
+	if err := writeLicenseReport(*out, entries, *format); err != nil {
+		fmt.Fprintf(os.Stderr, "license-report: %v\n", err)
+		return 1
+	}
+	violations := 0
+	for _, e := range entries {
+		if e.Status == "violation" {
+			violations++
+		}
+	}
+	fmt.Fprintf(os.Stderr, "%d packages, %d license violations\n", len(entries), violations)
+	if violations > 0 {
+		return 1
+	}
+	return 0
+}
+
+// writeLicenseReport writes entries to path, or stdout when path is empty.
+func writeLicenseReport(path string, entries []commands.LicenseReportEntry, format string) error {
+	if path == "" {
+		return commands.WriteLicenseReport(os.Stdout, entries, format)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := commands.WriteLicenseReport(f, entries, format); err != nil {
+		f.Close()
+		return err
+	}
+	// A failed close can mean the report never reached the disk
+	return f.Close()
+}
@@ -1,6 +1,9 @@
 package commands
 
-import "sync"
+import (
+	"strings"
+	"sync"
+)
 
 // pkgMetaCache caches NpmSearchObject per package name for this app session.
 var pkgMetaCache = struct {
@@ -21,26 +24,83 @@ func cacheSetPkg(name string, obj NpmSearchObject) {
 	pkgMetaCache.mu.Unlock()
 }
 
-// dlRangeCache caches downloads-over-time values per package and window days.
+// dlRangeCache caches daily downloads per package and window so switching
+// granularity does not refetch.
 var dlRangeCache = struct {
 	mu sync.RWMutex
-	m  map[string][]float64 // key: pkg|days
-}{m: make(map[string][]float64)}
+	m  map[string][]dailyDownloads // key: pkg|window
+}{m: make(map[string][]dailyDownloads)}
 
-// Optional secondary cache for time points could be added if needed later.
-
-func cacheGetDLRange(key string) ([]float64, bool) {
+func cacheGetDLRange(key string) ([]dailyDownloads, bool) {
 	dlRangeCache.mu.RLock()
 	v, ok := dlRangeCache.m[key]
 	dlRangeCache.mu.RUnlock()
 	return v, ok
 }
 
-func cacheSetDLRange(key string, values []float64) {
+func cacheSetDLRange(key string, days []dailyDownloads) {
 	dlRangeCache.mu.Lock()
 	// store a copy to be safe
-	vv := make([]float64, len(values))
-	copy(vv, values)
-	dlRangeCache.m[key] = vv
+	dd := make([]dailyDownloads, len(days))
+	copy(dd, days)
+	dlRangeCache.m[key] = dd
 	dlRangeCache.mu.Unlock()
 }
+
+// Full packuments can be several MB each (every version's manifest plus the
+// README), so only the most recently stored ones are kept. Abbreviated
+// documents are far smaller, and a footprint walk needs many of them.
+const (
+	maxCachedPackuments  = 64
+	maxCachedAbbreviated = 2048
+)
+
+// packumentCache caches registry documents per package name; abbreviated
+// documents use "corgi:" keys. Each kind drops its oldest entries beyond its
+// limit.
+var packumentCache = struct {
+	mu          sync.RWMutex
+	full, corgi boundedPackuments
+}{
+	full:  boundedPackuments{max: maxCachedPackuments, m: map[string]*packument{}},
+	corgi: boundedPackuments{max: maxCachedAbbreviated, m: map[string]*packument{}},
+}
+
+// boundedPackuments is a map that forgets the oldest keys beyond max.
+type boundedPackuments struct {
+	max   int
+	m     map[string]*packument
+	order []string
+}
+
+func (b *boundedPackuments) set(key string, p *packument) {
+	if _, ok := b.m[key]; !ok {
+		b.order = append(b.order, key)
+		if len(b.order) > b.max {
+			delete(b.m, b.order[0])
+			b.order = b.order[1:]
+		}
+	}
+	b.m[key] = p
+}
+
+func cacheGetPackument(name string) (*packument, bool) {
+	packumentCache.mu.RLock()
+	defer packumentCache.mu.RUnlock()
+	if strings.HasPrefix(name, "corgi:") {
+		v, ok := packumentCache.corgi.m[name]
+		return v, ok
+	}
+	v, ok := packumentCache.full.m[name]
+	return v, ok
+}
+
+func cacheSetPackument(name string, p *packument) {
+	packumentCache.mu.Lock()
+	defer packumentCache.mu.Unlock()
+	if strings.HasPrefix(name, "corgi:") {
+		packumentCache.corgi.set(name, p)
+		return
+	}
+	packumentCache.full.set(name, p)
+}
@@ -0,0 +1,26 @@
+package commands
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBoundedPackumentsDropsOldest(t *testing.T) {
+	b := boundedPackuments{max: 3, m: map[string]*packument{}}
+	for i := range 5 {
+		b.set(fmt.Sprint(i), &packument{})
+	}
+	// Replacing an entry does not count as a new one
+	b.set("4", &packument{Name: "four"})
+	if len(b.m) != 3 || len(b.order) != 3 {
+		t.Fatalf("kept %d entries (%d ordered), want 3", len(b.m), len(b.order))
+	}
+	for _, k := range []string{"0", "1"} {
+		if _, ok := b.m[k]; ok {
+			t.Errorf("%s still cached, want it dropped", k)
+		}
+	}
+	if b.m["4"].Name != "four" {
+		t.Errorf("entry 4 was not replaced")
+	}
+}
@@ -0,0 +1,293 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxTimelineVersions caps the timeline when the installed version is far
+// behind.
+const maxTimelineVersions = 20
+
+// ChangelogMsg carries a Markdown document describing what changed between
+// the installed and the latest version of a package.
+type ChangelogMsg struct {
+	Package  string
+	Markdown string
+	Err      error
+	Req      int // request sequence
+}
+
+// versionRe finds a semver-looking token in headings and tag names.
+var versionRe = regexp.MustCompile(`v?(\d+\.\d+\.\d+(?:-[0-9A-Za-z.-]+)?)`)
+
+// FetchChangelog builds a changelog for pkg from the packument's time map,
+// the repository's CHANGELOG and its GitHub release notes, limited to versions
+// after the one in the project's node_modules up to latest. When pkg is not
+// installed only the latest version is shown.
+func FetchChangelog(pkg string, req int) tea.Cmd {
+	return func() tea.Msg {
+		client := &http.Client{Timeout: 8 * time.Second}
+		p, err := fetchPackument(client, pkg)
+		if err != nil {
+			return ChangelogMsg{Package: pkg, Err: err, Req: req}
+		}
+		latest := p.DistTags["latest"]
+		if latest == "" {
+			return ChangelogMsg{Package: pkg, Err: errNoLatest, Req: req}
+		}
+		from := ""
+		if cwd, err := os.Getwd(); err == nil {
+			if pj := findPackageJSON(cwd); pj != "" {
+				from = installedVersion(filepath.Dir(pj), pkg)
+			}
+		}
+		inRange := changelogRange(from, latest)
+
+		var b strings.Builder
+		switch {
+		case from == "":
+			fmt.Fprintf(&b, "# %s %s\n\nNot installed; showing the latest release only.\n\n", pkg, latest)
+		case from == latest:
+			fmt.Fprintf(&b, "# %s %s\n\nThe installed version is the latest.\n\n", pkg, latest)
+		default:
+			fmt.Fprintf(&b, "# %s: %s → %s\n\n", pkg, from, latest)
+		}
+		b.WriteString(renderTimeline(p, latest, inRange))
+
+		repoURL := p.Repository.URL
+		dir := p.Repository.Directory
+		if v, ok := p.Versions[latest]; ok && v.Repository.URL != "" {
+			repoURL, dir = v.Repository.URL, v.Repository.Directory
+		}
+		owner, repo, err := parseGitHubRepo(repoURL)
+		if err != nil {
+			b.WriteString("\n_No GitHub repository found; release notes are unavailable._\n")
+			return ChangelogMsg{Package: pkg, Markdown: b.String(), Req: req}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if _, md := tryRawFilesCtx(ctx, owner, repo, dir, changelogNames); md != "" {
+			if sections := changelogSections(md, inRange); sections != "" {
+				b.WriteString("\n## CHANGELOG\n\n")
+				b.WriteString(sections)
+				b.WriteString("\n")
+			}
+		}
+		if notes := fetchReleaseNotes(ctx, client, owner, repo, pkg, inRange); notes != "" {
+			b.WriteString("\n## GitHub releases\n\n")
+			b.WriteString(notes)
+		}
+		return ChangelogMsg{Package: pkg, Markdown: b.String(), Req: req}
+	}
+}
+
+// changelogRange reports whether a version belongs in the changelog: after
+// installed up to latest, or only latest when nothing is installed.
+func changelogRange(installed, latest string) func(string) bool {
+	lv, latestOK := parseSemver(latest)
+	fv, installedOK := parseSemver(installed)
+	return func(v string) bool {
+		if installed == "" {
+			return v == latest
+		}
+		sv, ok := parseSemver(v)
+		if !ok || !installedOK {
+			return false
+		}
+		if latestOK && compareSemver(sv, lv) > 0 {
+			return false
+		}
+		return compareSemver(sv, fv) > 0
+	}
+}
+
+// changelogNames are the common changelog file names tried in the repository.
+var changelogNames = []string{"CHANGELOG.md", "Changelog.md", "changelog.md", "HISTORY.md", "History.md", "CHANGES.md"}
+
+// renderTimeline lists versions in range with their publish dates, newest first.
+func renderTimeline(p *packument, latest string, inRange func(string) bool) string {
+	latestSV, _ := parseSemver(latest)
+	versions := make([]string, 0, len(p.Versions))
+	for v := range p.Versions {
+		sv, ok := parseSemver(v)
+		if !ok || !inRange(v) {
+			continue
+		}
+		// skip prereleases unless the latest itself is one
+		if sv.pre != "" && latestSV.pre == "" {
+			continue
+		}
+		versions = append(versions, v)
+	}
+	sortVersions(versions)
+	// newest first
+	for i, j := 0, len(versions)-1; i < j; i, j = i+1, j-1 {
+		versions[i], versions[j] = versions[j], versions[i]
+	}
+	truncated := 0
+	if len(versions) > maxTimelineVersions {
+		truncated = len(versions) - maxTimelineVersions
+		versions = versions[:maxTimelineVersions]
+	}
+	if len(versions) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("## Release timeline\n\n| Version | Published |\n|---|---|\n")
+	for _, v := range versions {
+		when := "unknown"
+		if t, ok := p.publishTime(v); ok {
+			when = t.Format("2006-01-02")
+		}
+		fmt.Fprintf(&b, "| %s | %s |\n", v, when)
+	}
+	if truncated > 0 {
+		fmt.Fprintf(&b, "\n_…and %d older versions._\n", truncated)
+	}
+	return b.String()
+}
+
+// changelogSections returns the CHANGELOG sections whose heading names a
+// version accepted by keep. Sections are split at the heading level used by
+// most version headings.
+func changelogSections(md string, keep func(string) bool) string {
+	lines := strings.Split(md, "\n")
+	levels := map[int]int{}
+	for _, l := range lines {
+		if lvl := headingLevel(l); lvl > 0 && versionRe.MatchString(l) {
+			levels[lvl]++
+		}
+	}
+	level, best := 0, 0
+	for lvl, n := range levels {
+		if n > best || (n == best && lvl < level) {
+			level, best = lvl, n
+		}
+	}
+	if level == 0 {
+		return ""
+	}
+	var out strings.Builder
+	including := false
+	inFence := false
+	for _, l := range lines {
+		if strings.HasPrefix(strings.TrimSpace(l), "```") {
+			inFence = !inFence
+		}
+		if lvl := headingLevel(l); lvl > 0 && !inFence {
+			if lvl <= level {
+				including = false
+				if m := versionRe.FindStringSubmatch(l); lvl == level && m != nil && keep(m[1]) {
+					including = true
+				}
+			}
+			// shift headings to fit under our own "## CHANGELOG" heading
+			if including {
+				l = strings.Repeat("#", min(6, lvl-level+3)) + strings.TrimLeft(l, "#")
+			}
+		}
+		if including {
+			out.WriteString(l)
+			out.WriteString("\n")
+		}
+	}
+	return strings.TrimSpace(out.String())
+}
+
+// headingLevel returns the ATX heading level of line, or 0.
+func headingLevel(line string) int {
+	n := 0
+	for n < len(line) && line[n] == '#' {
+		n++
+	}
+	if n == 0 || n > 6 || n >= len(line) || line[n] != ' ' {
+		return 0
+	}
+	return n
+}
+
+// githubRelease is the subset of the GitHub releases API we render.
+type githubRelease struct {
+	TagName     string `json:"tag_name"`
+	Name        string `json:"name"`
+	Body        string `json:"body"`
+	Draft       bool   `json:"draft"`
+	PublishedAt string `json:"published_at"`
+}
+
+// fetchReleaseNotes renders GitHub release notes for tags in range. Monorepo
+// tags such as "other-pkg@1.0.0" are skipped unless they name pkg.
+func fetchReleaseNotes(ctx context.Context, client *http.Client, owner, repo, pkg string, keep func(string) bool) string {
+	u := "https://api.github.com/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repo) + "/releases?per_page=100"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return ""
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", "npm-tui (https://github.com/fredrikmwold/npm-tui)")
+	if tok := os.Getenv("GITHUB_TOKEN"); tok != "" {
+		req.Header.Set("Authorization", "Bearer "+tok)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return ""
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return ""
+	}
+	var releases []githubRelease
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return ""
+	}
+	type note struct {
+		version string
+		rel     githubRelease
+	}
+	var notes []note
+	for _, r := range releases {
+		if r.Draft {
+			continue
+		}
+		if at := strings.LastIndex(r.TagName, "@"); at > 0 && r.TagName[:at] != pkg {
+			continue
+		}
+		m := versionRe.FindStringSubmatch(r.TagName)
+		if m == nil || !keep(m[1]) {
+			continue
+		}
+		notes = append(notes, note{version: m[1], rel: r})
+	}
+	sort.SliceStable(notes, func(i, j int) bool { return compareVersionStrings(notes[i].version, notes[j].version) > 0 })
+	var b strings.Builder
+	for _, n := range notes {
+		title := n.rel.Name
+		if title == "" {
+			title = n.rel.TagName
+		}
+		fmt.Fprintf(&b, "### %s", title)
+		if len(n.rel.PublishedAt) >= 10 {
+			fmt.Fprintf(&b, " (%s)", n.rel.PublishedAt[:10])
+		}
+		b.WriteString("\n\n")
+		body := strings.TrimSpace(n.rel.Body)
+		if body == "" {
+			body = "_No release notes._"
+		}
+		b.WriteString(body)
+		b.WriteString("\n\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChangelogRange(t *testing.T) {
+	tests := []struct {
+		installed, latest, version string
+		want                       bool
+	}{
+		{"1.2.0", "1.4.0", "1.2.0", false},
+		{"1.2.0", "1.4.0", "1.3.0", true},
+		{"1.2.0", "1.4.0", "1.4.0", true},
+		{"1.2.0", "1.4.0", "1.5.0-beta.1", false},
+		{"1.2.0", "1.4.0", "not-a-version", false},
+		// nothing installed: only the latest section
+		{"", "1.4.0", "1.4.0", true},
+		{"", "1.4.0", "1.3.0", false},
+		{"", "1.4.0", "0.1.0", false},
+	}
+	for _, tt := range tests {
+		if got := changelogRange(tt.installed, tt.latest)(tt.version); got != tt.want {
+			t.Errorf("changelogRange(%q, %q)(%q) = %v, want %v", tt.installed, tt.latest, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestChangelogSections(t *testing.T) {
+	md := strings.Join([]string{
+		"# Changelog",
+		"## 1.4.0",
+		"- four",
+		"## [1.3.0] - 2024-01-02",
+		"- three",
+		"## 1.2.0",
+		"- two",
+	}, "\n")
+	got := changelogSections(md, changelogRange("", "1.4.0"))
+	if !strings.Contains(got, "four") || strings.Contains(got, "three") || strings.Contains(got, "two") {
+		t.Errorf("not installed: got %q, want only the 1.4.0 section", got)
+	}
+	got = changelogSections(md, changelogRange("1.2.0", "1.4.0"))
+	if !strings.Contains(got, "four") || !strings.Contains(got, "three") || strings.Contains(got, "two") {
+		t.Errorf("installed 1.2.0: got %q, want the 1.4.0 and 1.3.0 sections", got)
+	}
+}
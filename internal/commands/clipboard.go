@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// CopyMsg reports the result of copying text to the clipboard.
+type CopyMsg struct {
+	// What describes the copied text, e.g. "install command"
+	What string
+	Text string
+	// OSC52 is set when the text was handed to the terminal instead of the
+	// system clipboard
+	OSC52 bool
+	Err   error
+}
+
+// CopyViaTerminalMsg asks for text to be copied via OSC 52 because the
+// system clipboard is unavailable; see CopyViaTerminal.
+type CopyViaTerminalMsg struct {
+	What string
+	Text string
+}
+
+// CopyToClipboard copies text to the system clipboard. Over SSH, or when no
+// clipboard tool is available, it returns a CopyViaTerminalMsg instead.
+func CopyToClipboard(text, what string) tea.Cmd {
+	return func() tea.Msg {
+		remote := os.Getenv("SSH_TTY") != "" || os.Getenv("SSH_CONNECTION") != ""
+		if !remote && !clipboard.Unsupported {
+			if err := clipboard.WriteAll(text); err == nil {
+				return CopyMsg{What: what, Text: text}
+			}
+		}
+		return CopyViaTerminalMsg{What: what, Text: text}
+	}
+}
+
+// CopyViaTerminal asks the terminal to copy text with an OSC 52 sequence.
+// The sequence goes to the program's output while rendering is paused, so
+// it cannot interleave with a frame.
+func CopyViaTerminal(text, what string) tea.Cmd {
+	seq := osc52.New(text)
+	switch {
+	case os.Getenv("TMUX") != "":
+		seq = seq.Tmux()
+	case strings.HasPrefix(os.Getenv("TERM"), "screen"):
+		seq = seq.Screen()
+	}
+	return tea.Exec(&osc52Write{seq: seq}, func(err error) tea.Msg {
+		return CopyMsg{What: what, Text: text, OSC52: true, Err: err}
+	})
+}
+
+// osc52Write is a tea.ExecCommand that writes an OSC 52 sequence to the
+// program's output.
+type osc52Write struct {
+	seq osc52.Sequence
+	out io.Writer
+}
+
+func (w *osc52Write) Run() error {
+	_, err := w.seq.WriteTo(w.out)
+	return err
+}
+
+func (w *osc52Write) SetStdin(io.Reader)      {}
+func (w *osc52Write) SetStdout(out io.Writer) { w.out = out }
+func (w *osc52Write) SetStderr(io.Writer)     {}
@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"net/http"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ComparePackage holds the metrics shown for one package in the comparison view.
+type ComparePackage struct {
+	Name            string
+	Version         string
+	WeeklyDownloads int
+	License         string
+	UnpackedSize    int64
+	Dependencies    int
+	LastPublish     time.Time
+	Maintainers     []string
+	// TypeScript is "bundled", "@types" or "none"
+	TypeScript string
+	// Downloads holds weekly download totals for the last year
+	Downloads []DownloadPoint
+	Err       error
+}
+
+// CompareMsg is emitted when all packages for a comparison have been fetched.
+// Packages are in the same order as the requested names.
+type CompareMsg struct {
+	Packages []ComparePackage
+	Req      int // request sequence
+}
+
+// FetchCompare loads registry metadata, weekly downloads and a one-year
+// download trend for each name concurrently.
+func FetchCompare(names []string, req int) tea.Cmd {
+	return func() tea.Msg {
+		client := &http.Client{Timeout: 8 * time.Second}
+		result := make([]ComparePackage, len(names))
+		sem := make(chan struct{}, 4)
+		done := make(chan struct{}, len(names))
+		for i, nm := range names {
+			i, nm := i, nm
+			sem <- struct{}{}
+			go func() {
+				defer func() { <-sem; done <- struct{}{} }()
+				result[i] = fetchComparePackage(client, nm)
+			}()
+		}
+		for range names {
+			<-done
+		}
+		return CompareMsg{Packages: result, Req: req}
+	}
+}
+
+func fetchComparePackage(client *http.Client, name string) ComparePackage {
+	cp := ComparePackage{Name: name}
+	p, err := fetchPackument(client, name)
+	if err != nil {
+		cp.Err = err
+		return cp
+	}
+	if v, ok := p.latestVersion(); ok {
+		cp.Version = v.Version
+		cp.License = string(v.License)
+		cp.UnpackedSize = v.Dist.UnpackedSize
+		cp.Dependencies = len(v.Dependencies)
+		switch {
+		case v.Types != "" || v.Typings != "":
+			cp.TypeScript = "bundled"
+		case packageExists(client, typesPackageName(name)):
+			cp.TypeScript = "@types"
+		default:
+			cp.TypeScript = "none"
+		}
+		if t, ok := p.publishTime(v.Version); ok {
+			cp.LastPublish = t
+		}
+	}
+	if cp.License == "" {
+		cp.License = string(p.License)
+	}
+	for _, m := range p.Maintainers {
+		cp.Maintainers = append(cp.Maintainers, m.Name)
+	}
+	cp.WeeklyDownloads = fetchWeeklyDownloads(client, name)
+	if days, err := fetchDailyDownloads(client, name, Window1Year); err == nil {
+		cp.Downloads = aggregateDownloads(days, GranularityWeekly)
+	}
+	return cp
+}
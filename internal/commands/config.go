@@ -0,0 +1,217 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+)
+
+// Config is the user configuration read from
+// $XDG_CONFIG_HOME/npm-tui/config.json (or the platform equivalent).
+type Config struct {
+	License LicensePolicy `json:"license"`
+	// Keys maps action names (e.g. "install") to the keys that trigger them
+	Keys map[string][]string `json:"keys"`
+	// SearchSize is the number of search results to request
+	SearchSize int `json:"searchSize"`
+	// ChartWindow is the initial downloads chart window: 30d, 90d, 1y, 2y or all
+	ChartWindow string `json:"chartWindow"`
+	// PackageManager is used when the project has no lockfile
+	PackageManager PackageManager `json:"packageManager"`
+	// Theme names the initial theme, built in or from Themes
+	Theme string `json:"theme"`
+	// Themes defines user themes by name
+	Themes map[string]ThemeConfig `json:"themes"`
+}
+
+// ThemeConfig is a user theme: a built-in base theme with some palette colors
+// replaced, e.g. {"base": "light", "colors": {"mauve": "#8839ef"}}.
+type ThemeConfig struct {
+	Base   string            `json:"base"`
+	Colors map[string]string `json:"colors"`
+}
+
+// defaultSearchSize is the number of search results requested by default.
+const defaultSearchSize = 10
+
+// Window returns the configured initial chart window.
+func (c Config) Window() DownloadsWindow {
+	if w, ok := ParseDownloadsWindow(c.ChartWindow); ok {
+		return w
+	}
+	return Window1Year
+}
+
+// validate checks the settings that do not belong to a sub-policy.
+func (c Config) validate() error {
+	if c.SearchSize < 1 || c.SearchSize > 250 {
+		return fmt.Errorf("searchSize: %d is outside 1-250", c.SearchSize)
+	}
+	if c.ChartWindow != "" {
+		if _, ok := ParseDownloadsWindow(c.ChartWindow); !ok {
+			return fmt.Errorf("chartWindow: %q is not one of 30d, 90d, 1y, 2y, all", c.ChartWindow)
+		}
+	}
+	switch c.PackageManager {
+	case "", PMNPM, PMPNPM, PMYarn, PMBun:
+	default:
+		return fmt.Errorf("packageManager: %q is not one of npm, pnpm, yarn, bun", c.PackageManager)
+	}
+	return nil
+}
+
+// ConfigPath returns the location of the config file.
+func ConfigPath() (string, error) {
+	return configFile("config.json")
+}
+
+// configFile returns the location of a file in the config directory.
+func configFile(name string) (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "npm-tui", name), nil
+}
+
+// writeConfigFile writes v as indented JSON to the named file in the config
+// directory. It writes atomically so an interrupted save keeps the previous
+// contents.
+func writeConfigFile(name string, v any) error {
+	p, err := configFile(name)
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p), name+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(append(b, '\n')); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), p); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// configSaver writes snapshots of one config file one at a time. Saves are
+// numbered when requested, so a snapshot older than one already written is
+// dropped instead of overwriting it.
+type configSaver struct {
+	name    string
+	mu      sync.Mutex
+	next    atomic.Uint64
+	written uint64
+}
+
+// save returns a function that writes v unless a newer snapshot got there
+// first. Call save from Update, where requests are ordered.
+func (s *configSaver) save(v any) func() error {
+	seq := s.next.Add(1)
+	return func() error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if seq < s.written {
+			return nil
+		}
+		if err := writeConfigFile(s.name, v); err != nil {
+			return err
+		}
+		s.written = seq
+		return nil
+	}
+}
+
+// readConfigFile decodes the named file in the config directory into v. A
+// missing file leaves v untouched.
+func readConfigFile(name string, v any) error {
+	p, err := configFile(name)
+	if err != nil {
+		return err
+	}
+	b, err := os.ReadFile(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("%s: %s", p, describeJSONError(b, err))
+	}
+	return nil
+}
+
+// LoadConfig reads and validates the config file. A missing file yields the
+// defaults, which enforce nothing.
+func LoadConfig() (Config, error) {
+	cfg := Config{SearchSize: defaultSearchSize}
+	p, err := ConfigPath()
+	if err != nil {
+		return cfg, nil
+	}
+	b, err := os.ReadFile(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return cfg, fmt.Errorf("%s: %s", p, describeJSONError(b, err))
+	}
+	if err := cfg.validate(); err != nil {
+		return cfg, fmt.Errorf("%s: %w", p, err)
+	}
+	if err := cfg.License.validate(); err != nil {
+		return cfg, fmt.Errorf("%s: license: %w", p, err)
+	}
+	return cfg, nil
+}
+
+// describeJSONError adds the line and column to syntax and type errors.
+func describeJSONError(b []byte, err error) string {
+	var offset int64
+	var syn *json.SyntaxError
+	var typ *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syn):
+		offset = syn.Offset
+	case errors.As(err, &typ):
+		offset = typ.Offset
+		err = fmt.Errorf("%s must be %s, not %s", typ.Field, typ.Type, typ.Value)
+	default:
+		return err.Error()
+	}
+	line, col := 1, 1
+	for _, c := range b[:min(int(offset), len(b))] {
+		if c == '\n' {
+			line, col = line+1, 1
+		} else {
+			col++
+		}
+	}
+	return fmt.Sprintf("line %d, column %d: %v", line, col, err)
+}
@@ -0,0 +1,25 @@
+package commands
+
+import "testing"
+
+func TestConfigSaverDropsOlderSnapshots(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	s := &configSaver{name: "saver-test.json"}
+	older := s.save(History{Searches: []string{"react"}})
+	newer := s.save(History{Searches: []string{"react", "vue"}})
+	// The newer save wins the race to the lock
+	if err := newer(); err != nil {
+		t.Fatal(err)
+	}
+	if err := older(); err != nil {
+		t.Fatal(err)
+	}
+	var got History
+	if err := readConfigFile(s.name, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Searches) != 2 {
+		t.Errorf("file holds %v, want the newer snapshot", got.Searches)
+	}
+}
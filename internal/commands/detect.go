@@ -1,47 +1,139 @@
 package commands
 
 import (
+	"encoding/json"
 	"os"
+	"os/exec"
 	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 // detect.go: helper routines for finding package manager/installed packages
 
-// detectPackageManager inspects lockfiles to decide which package manager to use.
-// Defaults to npm if none detected.
-func detectPackageManager(cwd string) PackageManager {
+// detectPackageManager decides which package manager to use: the one declared
+// in package.json's packageManager field, else the owner of the nearest
+// lockfile. Defaults to fallback, or npm when that is empty, if none detected.
+func detectPackageManager(cwd string, fallback PackageManager) PackageManager {
+	if pm, _ := declaredPackageManager(cwd); pm != "" {
+		return pm
+	}
+	if pm, _ := lockfileManager(cwd); pm != "" {
+		return pm
+	}
+	// Fallback
+	if fallback != "" {
+		return fallback
+	}
+	return PMNPM
+}
+
+// lockfileManager returns the package manager owning the nearest lockfile at
+// or above cwd, and the lockfile's path.
+func lockfileManager(cwd string) (PackageManager, string) {
 	if cwd == "" {
 		if w, err := os.Getwd(); err == nil {
 			cwd = w
 		}
 	}
-	if cwd != "" {
-		tryFiles := []struct {
-			file string
-			pm   PackageManager
-		}{
-			{"pnpm-lock.yaml", PMPNPM},
-			{"bun.lockb", PMBun},
-			{"yarn.lock", PMYarn},
-			{"package-lock.json", PMNPM},
-		}
-		dir := cwd
-		for {
-			for _, t := range tryFiles {
-				if _, err := os.Stat(filepath.Join(dir, t.file)); err == nil {
-					return t.pm
-				}
-			}
-			parent := filepath.Dir(dir)
-			if parent == dir { // reached filesystem root
-				break
+	if cwd == "" {
+		return "", ""
+	}
+	tryFiles := []struct {
+		file string
+		pm   PackageManager
+	}{
+		{"pnpm-lock.yaml", PMPNPM},
+		{"bun.lock", PMBun},
+		{"bun.lockb", PMBun},
+		{"yarn.lock", PMYarn},
+		{"package-lock.json", PMNPM},
+	}
+	dir := cwd
+	for {
+		for _, t := range tryFiles {
+			p := filepath.Join(dir, t.file)
+			if _, err := os.Stat(p); err == nil {
+				return t.pm, p
 			}
-			dir = parent
 		}
+		parent := filepath.Dir(dir)
+		if parent == dir { // reached filesystem root
+			break
+		}
+		dir = parent
 	}
+	return "", ""
+}
 
-	// Fallback
-	return PMNPM
+// ProjectInfoMsg describes the project in the working directory for the
+// status bar.
+type ProjectInfoMsg struct {
+	// Dir is the directory of the nearest package.json, or the working
+	// directory when there is none
+	Dir            string
+	PackageManager PackageManager
+	// Version is the package manager's version shown to the user: the
+	// declared one when package.json pins it, else the installed one
+	Version string
+	// Lockfile is PackageManager's lockfile; empty when there is none
+	Lockfile string
+	// Declared is the version pinned by package.json's packageManager field
+	// and Installed the version of the binary on PATH; either may be empty
+	Declared  string
+	Installed string
+	// Corepack is set when corepack is on PATH
+	Corepack bool
+}
+
+// Mismatch reports whether the installed package manager differs from the
+// version package.json declares.
+func (p ProjectInfoMsg) Mismatch() bool {
+	return p.Declared != "" && p.Installed != p.Declared
+}
+
+// DetectProject finds the project directory, its package manager and the
+// versions declared and installed, falling back to preferred when neither
+// the packageManager field nor a lockfile names one.
+func DetectProject(preferred PackageManager) tea.Cmd {
+	return func() tea.Msg {
+		cwd, _ := os.Getwd()
+		return projectInfo(cwd, preferred)
+	}
+}
+
+// projectInfo describes the project at cwd.
+func projectInfo(cwd string, preferred PackageManager) ProjectInfoMsg {
+	info := ProjectInfoMsg{Dir: cwd}
+	if p := findPackageJSON(cwd); p != "" {
+		info.Dir = filepath.Dir(p)
+	}
+	info.PackageManager = detectPackageManager(cwd, preferred)
+	if pm, lock := lockfileManager(cwd); pm == info.PackageManager {
+		info.Lockfile = lock
+	}
+	if pm, v := declaredPackageManager(cwd); pm == info.PackageManager {
+		info.Declared = v
+	}
+	info.Installed = binaryVersion(cwd, info.PackageManager)
+	info.Version = info.Installed
+	if info.Declared != "" {
+		info.Version = info.Declared
+	}
+	_, err := exec.LookPath("corepack")
+	info.Corepack = err == nil
+	return info
+}
+
+// Toolchain returns what installs run in the project: the package manager
+// on PATH, or the declared version when run through corepack. The declared
+// version alone does not pick the commands; the binary may not have them.
+func (p ProjectInfoMsg) Toolchain(corepack bool) Toolchain {
+	t := Toolchain{PM: p.PackageManager, Version: p.Installed, Corepack: corepack}
+	if corepack && p.Declared != "" {
+		t.Version = p.Declared
+	}
+	return t
 }
 
 // isPkgInstalled checks if node_modules/<pkg>/package.json exists (supports scopes).
@@ -61,3 +153,21 @@ func isPkgInstalled(cwd, name string) bool {
 	}
 	return false
 }
+
+// installedVersion returns the version recorded in node_modules/<pkg>/package.json.
+func installedVersion(cwd, name string) string {
+	if cwd == "" {
+		return ""
+	}
+	b, err := os.ReadFile(filepath.Join(cwd, "node_modules", name, "package.json"))
+	if err != nil {
+		return ""
+	}
+	var data struct {
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return ""
+	}
+	return data.Version
+}
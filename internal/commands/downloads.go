@@ -0,0 +1,225 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// DownloadsWindow selects how far back the downloads chart reaches, in days.
+// WindowAllTime reaches back to the first day the downloads API has data for.
+type DownloadsWindow int
+
+const (
+	Window30Days  DownloadsWindow = 30
+	Window90Days  DownloadsWindow = 90
+	Window1Year   DownloadsWindow = 365
+	Window2Years  DownloadsWindow = 730
+	WindowAllTime DownloadsWindow = 0
+)
+
+// DownloadsWindows lists the selectable windows in cycling order.
+var DownloadsWindows = []DownloadsWindow{Window30Days, Window90Days, Window1Year, Window2Years, WindowAllTime}
+
+func (w DownloadsWindow) String() string {
+	switch w {
+	case Window30Days:
+		return "30d"
+	case Window90Days:
+		return "90d"
+	case Window1Year:
+		return "1y"
+	case Window2Years:
+		return "2y"
+	case WindowAllTime:
+		return "all"
+	default:
+		return strconv.Itoa(int(w)) + "d"
+	}
+}
+
+// ParseDownloadsWindow parses a window name as printed by String.
+func ParseDownloadsWindow(s string) (DownloadsWindow, bool) {
+	for _, w := range DownloadsWindows {
+		if w.String() == s {
+			return w, true
+		}
+	}
+	return 0, false
+}
+
+// Granularity selects the bucket size used to aggregate daily downloads.
+type Granularity int
+
+const (
+	GranularityDaily Granularity = iota
+	GranularityWeekly
+	GranularityMonthly
+)
+
+// Granularities lists the selectable granularities in cycling order.
+var Granularities = []Granularity{GranularityDaily, GranularityWeekly, GranularityMonthly}
+
+func (g Granularity) String() string {
+	switch g {
+	case GranularityDaily:
+		return "daily"
+	case GranularityMonthly:
+		return "monthly"
+	default:
+		return "weekly"
+	}
+}
+
+// downloadsEpoch is the first day the npm downloads API has data for.
+var downloadsEpoch = time.Date(2015, time.January, 10, 0, 0, 0, 0, time.UTC)
+
+// maxRangeMonths is the longest span the downloads range API serves per request.
+const maxRangeMonths = 18
+
+// dailyDownloads is one day of the /downloads/range response.
+type dailyDownloads struct {
+	Day       time.Time
+	Downloads int
+}
+
+// FetchDownloadsRange fetches downloads for a package over the given window,
+// aggregated into the requested granularity, and returns an
+// NpmDownloadsRangeMsg. Values are ordered oldest..newest.
+func FetchDownloadsRange(pkg string, window DownloadsWindow, g Granularity) tea.Cmd {
+	return func() tea.Msg {
+		if pkg == "" || window < 0 {
+			return NpmDownloadsRangeMsg{Package: pkg, Window: window, Granularity: g}
+		}
+		client := &http.Client{Timeout: 8 * time.Second}
+		days, err := fetchDailyDownloads(client, pkg, window)
+		if err != nil {
+			return NpmDownloadsRangeMsg{Package: pkg, Window: window, Granularity: g, Err: err}
+		}
+		pts := aggregateDownloads(days, g)
+		vals := make([]float64, 0, len(pts))
+		for _, p := range pts {
+			vals = append(vals, p.Value)
+		}
+		return NpmDownloadsRangeMsg{Package: pkg, Window: window, Granularity: g, Values: vals, Points: pts}
+	}
+}
+
+// fetchDailyDownloads returns per-day downloads for the window, splitting the
+// request into chunks because the API caps each range at 18 months.
+func fetchDailyDownloads(client *http.Client, pkg string, window DownloadsWindow) ([]dailyDownloads, error) {
+	key := pkg + "|" + window.String()
+	if days, ok := cacheGetDLRange(key); ok {
+		return days, nil
+	}
+	// Compute date window: inclusive start:end, yesterday to avoid a partial current day
+	now := time.Now().UTC()
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, -1)
+	start := downloadsEpoch
+	if window != WindowAllTime {
+		start = end.AddDate(0, 0, -(int(window) - 1))
+	}
+	var out []dailyDownloads
+	for chunkStart := start; !chunkStart.After(end); {
+		chunkEnd := chunkStart.AddDate(0, maxRangeMonths, -1)
+		if chunkEnd.After(end) {
+			chunkEnd = end
+		}
+		days, err := fetchDownloadsChunk(client, pkg, chunkStart, chunkEnd)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, days...)
+		chunkStart = chunkEnd.AddDate(0, 0, 1)
+	}
+	if window == WindowAllTime {
+		// Drop the leading run of zero days before the package was published
+		i := 0
+		for i < len(out) && out[i].Downloads == 0 {
+			i++
+		}
+		out = out[i:]
+	}
+	cacheSetDLRange(key, out)
+	return out, nil
+}
+
+// fetchDownloadsChunk requests a single range (at most 18 months).
+func fetchDownloadsChunk(client *http.Client, pkg string, start, end time.Time) ([]dailyDownloads, error) {
+	u := "https://api.npmjs.org/downloads/range/" + start.Format("2006-01-02") + ":" + end.Format("2006-01-02") + "/" + url.PathEscape(pkg)
+	resp, err := client.Get(u)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloads API returned %s", resp.Status)
+	}
+	var parsed rangeDownloadsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, err
+	}
+	out := make([]dailyDownloads, 0, len(parsed.Downloads))
+	for _, d := range parsed.Downloads {
+		t, err := time.Parse("2006-01-02", d.Day)
+		if err != nil {
+			// skip invalid date entries
+			continue
+		}
+		out = append(out, dailyDownloads{Day: t, Downloads: d.Downloads})
+	}
+	return out, nil
+}
+
+// aggregateDownloads buckets daily downloads into the requested granularity.
+// Weekly and monthly buckets only include complete ISO weeks / calendar months
+// to avoid skew from partial first/last buckets; weekly totals match the
+// "Weekly Downloads" metric. Points sit in the middle of their bucket.
+func aggregateDownloads(days []dailyDownloads, g Granularity) []DownloadPoint {
+	pts := make([]DownloadPoint, 0, 64)
+	if g == GranularityDaily {
+		for _, d := range days {
+			pts = append(pts, DownloadPoint{Time: d.Day, Value: float64(d.Downloads)})
+		}
+		return pts
+	}
+	var curKey string
+	var sum, count, size int
+	var bucketStart time.Time
+	flush := func() {
+		if count > 0 && count == size {
+			mid := bucketStart.AddDate(0, 0, size/2)
+			pts = append(pts, DownloadPoint{Time: mid, Value: float64(sum)})
+		}
+		sum = 0
+		count = 0
+	}
+	for _, d := range days {
+		var k string
+		var n int
+		if g == GranularityMonthly {
+			k = d.Day.Format("2006-01")
+			// days in month: day 0 of next month
+			n = time.Date(d.Day.Year(), d.Day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+		} else {
+			y, w := d.Day.ISOWeek()
+			k = fmt.Sprintf("%04d-%02d", y, w)
+			n = 7
+		}
+		if k != curKey {
+			flush()
+			curKey = k
+			bucketStart = d.Day
+			size = n
+		}
+		sum += d.Downloads
+		count++
+	}
+	flush()
+	return pts
+}
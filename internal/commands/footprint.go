@@ -0,0 +1,167 @@
+package commands
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxFootprintPackages bounds the transitive walk so huge trees cannot keep
+// the app busy for minutes; results beyond it are reported as partial.
+const maxFootprintPackages = 2000
+
+// PackageSizeMsg carries size information for the latest version of a package
+// and an estimate of its full install footprint.
+type PackageSizeMsg struct {
+	Package      string
+	UnpackedSize int64
+	FileCount    int
+	Dependencies int
+	// TotalSize is the summed unpacked size of the package and its
+	// transitive dependency closure (deduplicated by name@version).
+	TotalSize     int64
+	TotalPackages int
+	// Partial is set when some dependencies could not be resolved or the
+	// walk hit maxFootprintPackages.
+	Partial bool
+	Err     error
+}
+
+// footprintWorkers is the number of packuments fetched concurrently while
+// walking a dependency closure.
+const footprintWorkers = 6
+
+// FetchPackageSize resolves the transitive dependency closure of the latest
+// version of pkg against the registry and sums unpacked sizes. Cancelling
+// ctx stops the walk; the result then carries ctx's error.
+func FetchPackageSize(ctx context.Context, pkg string) tea.Cmd {
+	return func() tea.Msg {
+		client := &http.Client{Timeout: 8 * time.Second}
+		root, err := fetchAbbreviatedPackument(client, pkg)
+		if err != nil {
+			return PackageSizeMsg{Package: pkg, Err: err}
+		}
+		latest, ok := root.resolveVersion("latest")
+		if !ok {
+			return PackageSizeMsg{Package: pkg, Err: errNoLatest}
+		}
+		msg := PackageSizeMsg{
+			Package:      pkg,
+			UnpackedSize: latest.Dist.UnpackedSize,
+			FileCount:    latest.Dist.FileCount,
+			Dependencies: len(latest.Dependencies),
+		}
+		msg.TotalSize, msg.TotalPackages, msg.Partial = walkFootprint(ctx, client, pkg, latest)
+		if err := ctx.Err(); err != nil {
+			return PackageSizeMsg{Package: pkg, Err: err}
+		}
+		return msg
+	}
+}
+
+// footprintDep is a dependency waiting to be resolved by walkFootprint.
+type footprintDep struct {
+	name, spec string
+}
+
+// walkFootprint visits the dependency closure of root with a fixed pool of
+// footprintWorkers pulling from a shared queue, and sums unpacked sizes.
+// Each name@spec is queued once.
+func walkFootprint(ctx context.Context, client *http.Client, rootName string, root packumentVersion) (total int64, count int, partial bool) {
+	var (
+		mu     sync.Mutex
+		cond   = sync.NewCond(&mu)
+		queue  []footprintDep
+		queued = map[string]bool{}
+		seen   = map[string]bool{rootName + "@" + root.Version: true}
+		// active counts dependencies taken off the queue but not yet resolved
+		active int
+		wg     sync.WaitGroup
+	)
+	total = root.Dist.UnpackedSize
+	count = 1
+	// enqueue must be called with mu held
+	enqueue := func(deps map[string]string) {
+		for name, spec := range deps {
+			if k := name + "@" + spec; !queued[k] {
+				queued[k] = true
+				queue = append(queue, footprintDep{name: name, spec: spec})
+			}
+		}
+	}
+	enqueue(root.Dependencies)
+	// Wake idle workers so they notice the cancellation
+	stop := context.AfterFunc(ctx, func() {
+		mu.Lock()
+		cond.Broadcast()
+		mu.Unlock()
+	})
+	defer stop()
+
+	worker := func() {
+		defer wg.Done()
+		mu.Lock()
+		defer mu.Unlock()
+		for {
+			for len(queue) == 0 && active > 0 && ctx.Err() == nil {
+				cond.Wait()
+			}
+			if len(queue) == 0 || ctx.Err() != nil {
+				// Done or cancelled; let the other workers see it too
+				cond.Broadcast()
+				return
+			}
+			dep := queue[0]
+			queue = queue[1:]
+			active++
+			mu.Unlock()
+			name, v, ok := resolveFootprintDep(client, dep)
+			mu.Lock()
+			active--
+			switch key := name + "@" + v.Version; {
+			case !ok:
+				partial = true
+			case seen[key]:
+			case count >= maxFootprintPackages:
+				partial = true
+				queue = nil
+			default:
+				seen[key] = true
+				total += v.Dist.UnpackedSize
+				count++
+				enqueue(v.Dependencies)
+			}
+			cond.Broadcast()
+		}
+	}
+	for range footprintWorkers {
+		wg.Add(1)
+		go worker()
+	}
+	wg.Wait()
+	return total, count, partial || ctx.Err() != nil
+}
+
+// resolveFootprintDep resolves dep to a registry version. ok is false for
+// git URLs, file: paths and other non-registry specs, and on fetch errors.
+func resolveFootprintDep(client *http.Client, dep footprintDep) (string, packumentVersion, bool) {
+	name, spec := dep.name, dep.spec
+	// npm aliases: "npm:real-name@range"
+	if rest, ok := strings.CutPrefix(spec, "npm:"); ok {
+		if at := strings.LastIndex(rest, "@"); at > 0 {
+			name, spec = rest[:at], rest[at+1:]
+		} else {
+			name, spec = rest, "latest"
+		}
+	}
+	p, err := fetchAbbreviatedPackument(client, name)
+	if err != nil {
+		return name, packumentVersion{}, false
+	}
+	v, ok := p.resolveVersion(spec)
+	return name, v, ok
+}
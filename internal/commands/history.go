@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxHistory is the number of past searches kept.
+const maxHistory = 200
+
+// History is the search history and the named saved queries, kept in
+// history.json next to the config file.
+type History struct {
+	// Searches are past queries, oldest first, without duplicates
+	Searches []string     `json:"searches"`
+	Saved    []SavedQuery `json:"saved"`
+}
+
+// SavedQuery is a search kept under a name, e.g. "react state libs" for
+// "keywords:react state".
+type SavedQuery struct {
+	Name  string `json:"name"`
+	Query string `json:"query"`
+}
+
+// AddSearch records q as the latest search, dropping an earlier copy and the
+// oldest searches beyond maxHistory.
+func (h *History) AddSearch(q string) {
+	h.Searches = slices.DeleteFunc(h.Searches, func(s string) bool { return s == q })
+	h.Searches = append(h.Searches, q)
+	if n := len(h.Searches) - maxHistory; n > 0 {
+		h.Searches = h.Searches[n:]
+	}
+}
+
+// Save stores q under name, replacing a saved query with the same name.
+func (h *History) Save(name, q string) {
+	for i, s := range h.Saved {
+		if s.Name == name {
+			h.Saved[i].Query = q
+			return
+		}
+	}
+	h.Saved = append(h.Saved, SavedQuery{Name: name, Query: q})
+}
+
+// Forget removes the saved query called name.
+func (h *History) Forget(name string) {
+	h.Saved = slices.DeleteFunc(h.Saved, func(s SavedQuery) bool { return s.Name == name })
+}
+
+// HistoryMsg carries the history read at startup.
+type HistoryMsg struct {
+	History History
+	Err     error
+}
+
+// HistorySavedMsg reports the result of writing the history.
+type HistorySavedMsg struct {
+	Err error
+}
+
+// historyFile is the name of the history file in the config directory.
+const historyFile = "history.json"
+
+// historySaver keeps history saves in order.
+var historySaver = &configSaver{name: historyFile}
+
+// LoadHistory reads the history file. A missing file yields an empty history.
+func LoadHistory() tea.Cmd {
+	return func() tea.Msg {
+		var h History
+		if err := readConfigFile(historyFile, &h); err != nil {
+			return HistoryMsg{Err: err}
+		}
+		return HistoryMsg{History: h}
+	}
+}
+
+// SaveHistory writes h to the history file. Saves run one at a time, and
+// one requested earlier never overwrites a later one.
+func SaveHistory(h History) tea.Cmd {
+	// Copy the slices; the caller keeps appending to its history
+	h.Searches = slices.Clone(h.Searches)
+	h.Saved = slices.Clone(h.Saved)
+	save := historySaver.save(h)
+	return func() tea.Msg {
+		return HistorySavedMsg{Err: save()}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -36,7 +37,10 @@ const (
 // predictable behavior when users trigger several actions quickly.
 var installMutex = make(chan struct{}, 1)
 
-func InstallNPM(pkg string, dev bool) tea.Cmd {
+// InstallNPM installs or updates pkg with the project's package manager,
+// falling back to preferred when none is declared or locked. With corepack
+// set, the package manager runs through corepack.
+func InstallNPM(pkg string, dev bool, preferred PackageManager, corepack bool) tea.Cmd {
 	return func() tea.Msg {
 		if pkg == "" {
 			return NpmInstallMsg{Package: pkg, Dev: dev, Err: nil}
@@ -45,59 +49,16 @@ func InstallNPM(pkg string, dev bool) tea.Cmd {
 		installMutex <- struct{}{}
 		defer func() { <-installMutex }()
 
-		// Decide which package manager to use based on project files
+		// Decide which package manager and version to use based on project files
 		wd, _ := os.Getwd()
-		pm := detectPackageManager(wd)
+		tc := resolveToolchain(wd, preferred, corepack)
+		pm := tc.PM
 
 		// Re-check installed state within the critical section to avoid
 		// stale decisions when multiple actions are queued.
 		installed := isPkgInstalled(wd, pkg)
 
-		var cmdName string
-		var args []string
-		switch pm {
-		case PMPNPM:
-			cmdName = "pnpm"
-			if installed {
-				args = []string{"up"}
-			} else {
-				args = []string{"add"}
-				if dev {
-					args = append(args, "--save-dev")
-				}
-			}
-			args = append(args, pkg)
-		case PMYarn:
-			cmdName = "yarn"
-			if installed {
-				args = []string{"upgrade", "--latest", pkg}
-			} else {
-				args = []string{"add"}
-				if dev {
-					args = append(args, "-D")
-				}
-				args = append(args, pkg)
-			}
-		case PMBun:
-			cmdName = "bun"
-			if installed {
-				args = []string{"upgrade", pkg}
-			} else {
-				args = []string{"add"}
-				if dev {
-					args = append(args, "-d")
-				}
-				args = append(args, pkg)
-			}
-		default: // npm
-			cmdName = "npm"
-			// Use install <pkg>@latest for both installed and new; add --save-dev for dev.
-			args = []string{"install"}
-			if dev && !installed { // keep dev flag only for fresh installs
-				args = append(args, "--save-dev")
-			}
-			args = append(args, pkg+"@latest")
-		}
+		cmdName, args := tc.installArgs(pkg, dev, installed)
 
 		// Timeout per actual execution; starts after we acquired the mutex.
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
@@ -124,4 +85,9 @@ func InstallNPM(pkg string, dev bool) tea.Cmd {
 	}
 }
 
-//
+// InstallCommand returns the shell command that adds pkg with tc, e.g.
+// "pnpm add react", for copying.
+func InstallCommand(pkg string, dev bool, tc Toolchain) string {
+	name, args := tc.installArgs(pkg, dev, false)
+	return name + " " + strings.Join(args, " ")
+}
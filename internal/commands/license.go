@@ -0,0 +1,239 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LicensePolicy lists SPDX identifiers that are allowed or denied. Deny wins
+// over allow; with an empty allow list every license that is not denied is
+// allowed.
+type LicensePolicy struct {
+	Allow []string `json:"allow"`
+	Deny  []string `json:"deny"`
+	// Enforce blocks violating installs outright instead of asking to override
+	Enforce bool `json:"enforce"`
+}
+
+// LicenseVerdict is the outcome of checking a license expression against a policy.
+type LicenseVerdict struct {
+	License   string
+	Violation bool
+	// Reason explains a violation, e.g. "GPL-3.0-only is denied"
+	Reason string
+}
+
+// Active reports whether the policy restricts anything.
+func (p LicensePolicy) Active() bool { return len(p.Allow) > 0 || len(p.Deny) > 0 }
+
+// Check evaluates an SPDX license expression such as "MIT OR Apache-2.0".
+// An OR expression passes when any alternative passes, AND needs all parts
+// to pass, and "X WITH exception" is judged by X. Missing or non-SPDX
+// licenses only violate a policy with an allow list.
+func (p LicensePolicy) Check(license string) LicenseVerdict {
+	v := LicenseVerdict{License: license}
+	if !p.Active() {
+		return v
+	}
+	if strings.TrimSpace(license) == "" {
+		if len(p.Allow) > 0 {
+			v.Violation, v.Reason = true, "no license declared"
+		}
+		return v
+	}
+	expr, err := parseSPDX(license)
+	if err != nil {
+		if len(p.Allow) > 0 {
+			v.Violation, v.Reason = true, "not an SPDX expression"
+		}
+		return v
+	}
+	if bad := p.failing(expr); len(bad) > 0 {
+		v.Violation, v.Reason = true, strings.Join(bad, ", ")
+	}
+	return v
+}
+
+// failing returns why expr is not permitted, or nil when it is.
+func (p LicensePolicy) failing(e *spdxExpr) []string {
+	switch e.op {
+	case "OR":
+		var first []string
+		for _, a := range e.args {
+			bad := p.failing(a)
+			if len(bad) == 0 {
+				return nil
+			}
+			if first == nil {
+				first = bad
+			}
+		}
+		return first
+	case "AND":
+		var all []string
+		for _, a := range e.args {
+			all = append(all, p.failing(a)...)
+		}
+		return all
+	}
+	switch {
+	case licenseIn(e.id, p.Deny):
+		return []string{e.id + " is denied"}
+	case len(p.Allow) > 0 && !licenseIn(e.id, p.Allow):
+		return []string{e.id + " is not allowed"}
+	}
+	return nil
+}
+
+// licenseIn reports whether id is listed, ignoring case and an "or later" +.
+func licenseIn(id string, list []string) bool {
+	bare := strings.TrimSuffix(id, "+")
+	for _, l := range list {
+		if strings.EqualFold(l, id) || strings.EqualFold(l, bare) {
+			return true
+		}
+	}
+	return false
+}
+
+// validate rejects policy entries that are not single SPDX identifiers and
+// identifiers listed as both allowed and denied.
+func (p LicensePolicy) validate() error {
+	for _, list := range [][]string{p.Allow, p.Deny} {
+		for _, id := range list {
+			if e, err := parseSPDX(id); err != nil || e.op != "" {
+				return fmt.Errorf("%q is not a single SPDX identifier", id)
+			}
+		}
+	}
+	for _, id := range p.Allow {
+		if licenseIn(id, p.Deny) {
+			return fmt.Errorf("%q is both allowed and denied", id)
+		}
+	}
+	return nil
+}
+
+// spdxExpr is a parsed SPDX license expression: either a license id or an
+// AND/OR over sub-expressions. WITH exceptions are dropped.
+type spdxExpr struct {
+	op   string // "AND", "OR" or "" for a single id
+	id   string
+	args []*spdxExpr
+}
+
+// parseSPDX parses expressions like "(MIT OR Apache-2.0) AND BSD-3-Clause".
+// AND binds tighter than OR, as in the SPDX specification.
+func parseSPDX(s string) (*spdxExpr, error) {
+	ps := &spdxParser{toks: spdxTokens(s)}
+	e, err := ps.or()
+	if err != nil {
+		return nil, err
+	}
+	if ps.pos < len(ps.toks) {
+		return nil, fmt.Errorf("unexpected %q", ps.toks[ps.pos])
+	}
+	return e, nil
+}
+
+// spdxTokens splits an expression into ids, keywords and parentheses.
+func spdxTokens(s string) []string {
+	s = strings.NewReplacer("(", " ( ", ")", " ) ").Replace(s)
+	return strings.Fields(s)
+}
+
+type spdxParser struct {
+	toks []string
+	pos  int
+}
+
+func (ps *spdxParser) peek() string {
+	if ps.pos < len(ps.toks) {
+		return ps.toks[ps.pos]
+	}
+	return ""
+}
+
+func (ps *spdxParser) or() (*spdxExpr, error) {
+	return ps.binary("OR", ps.and)
+}
+
+func (ps *spdxParser) and() (*spdxExpr, error) {
+	return ps.binary("AND", ps.with)
+}
+
+// binary parses operands joined by op and flattens them into one node.
+func (ps *spdxParser) binary(op string, operand func() (*spdxExpr, error)) (*spdxExpr, error) {
+	e, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	args := []*spdxExpr{e}
+	for strings.EqualFold(ps.peek(), op) {
+		ps.pos++
+		e, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, e)
+	}
+	if len(args) == 1 {
+		return args[0], nil
+	}
+	return &spdxExpr{op: op, args: args}, nil
+}
+
+func (ps *spdxParser) with() (*spdxExpr, error) {
+	e, err := ps.atom()
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(ps.peek(), "WITH") {
+		ps.pos++
+		if !isSPDXID(ps.peek()) {
+			return nil, fmt.Errorf("missing exception after WITH")
+		}
+		ps.pos++
+	}
+	return e, nil
+}
+
+func (ps *spdxParser) atom() (*spdxExpr, error) {
+	t := ps.peek()
+	switch {
+	case t == "(":
+		ps.pos++
+		e, err := ps.or()
+		if err != nil {
+			return nil, err
+		}
+		if ps.peek() != ")" {
+			return nil, fmt.Errorf("missing )")
+		}
+		ps.pos++
+		return e, nil
+	case isSPDXID(t):
+		ps.pos++
+		return &spdxExpr{id: t}, nil
+	case t == "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	return nil, fmt.Errorf("unexpected %q", t)
+}
+
+// isSPDXID reports whether t can be a license or exception id (letters,
+// digits, '.', '-', a trailing '+', and LicenseRef-/DocumentRef- prefixes).
+func isSPDXID(t string) bool {
+	if t == "" || strings.EqualFold(t, "AND") || strings.EqualFold(t, "OR") || strings.EqualFold(t, "WITH") {
+		return false
+	}
+	for i, r := range t {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == ':':
+		case r == '+' && i == len(t)-1:
+		default:
+			return false
+		}
+	}
+	return true
+}
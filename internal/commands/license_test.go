@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+)
+
+// spdxString renders e with explicit parentheses around every AND/OR.
+func spdxString(e *spdxExpr) string {
+	if e.op == "" {
+		return e.id
+	}
+	parts := make([]string, len(e.args))
+	for i, a := range e.args {
+		parts[i] = spdxString(a)
+	}
+	return "(" + strings.Join(parts, " "+e.op+" ") + ")"
+}
+
+func TestParseSPDX(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "MIT", want: "MIT"},
+		{in: "MIT OR Apache-2.0", want: "(MIT OR Apache-2.0)"},
+		{in: "MIT AND Apache-2.0 OR BSD-3-Clause", want: "((MIT AND Apache-2.0) OR BSD-3-Clause)"},
+		{in: "MIT OR Apache-2.0 AND BSD-3-Clause", want: "(MIT OR (Apache-2.0 AND BSD-3-Clause))"},
+		{in: "(MIT OR Apache-2.0) AND BSD-3-Clause", want: "((MIT OR Apache-2.0) AND BSD-3-Clause)"},
+		{in: "MIT OR ISC OR 0BSD", want: "(MIT OR ISC OR 0BSD)"},
+		{in: "mit or isc and 0bsd", want: "(mit OR (isc AND 0bsd))"},
+		// WITH binds tighter than AND and OR; the exception is dropped
+		{in: "GPL-2.0-only WITH Classpath-exception-2.0", want: "GPL-2.0-only"},
+		{in: "GPL-2.0-only WITH Classpath-exception-2.0 OR MIT", want: "(GPL-2.0-only OR MIT)"},
+		{in: "MIT AND GPL-2.0+ WITH Bison-exception-2.2", want: "(MIT AND GPL-2.0+)"},
+		{in: "LicenseRef-Custom", want: "LicenseRef-Custom"},
+		{in: "", wantErr: true},
+		{in: "MIT OR", wantErr: true},
+		{in: "(MIT OR ISC", wantErr: true},
+		{in: "MIT ISC", wantErr: true},
+		{in: "GPL-2.0-only WITH", wantErr: true},
+		{in: "SEE LICENSE IN LICENSE.md", wantErr: true},
+	}
+	for _, tt := range tests {
+		e, err := parseSPDX(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseSPDX(%q) = %s, want an error", tt.in, spdxString(e))
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseSPDX(%q) error: %v", tt.in, err)
+			continue
+		}
+		if got := spdxString(e); got != tt.want {
+			t.Errorf("parseSPDX(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLicensePolicyCheck(t *testing.T) {
+	deny := LicensePolicy{Deny: []string{"GPL-3.0-only", "MIT"}}
+	allow := LicensePolicy{Allow: []string{"MIT", "Apache-2.0", "GPL-2.0-only"}}
+	tests := []struct {
+		name    string
+		policy  LicensePolicy
+		license string
+		want    bool // violation
+	}{
+		{"no policy", LicensePolicy{}, "GPL-3.0-only", false},
+		{"denied", deny, "GPL-3.0-only", true},
+		{"deny is case-insensitive", deny, "gpl-3.0-only", true},
+		{"OR passes with one acceptable side", deny, "GPL-3.0-only OR ISC", false},
+		{"AND needs every part", deny, "ISC AND GPL-3.0-only", true},
+		{"AND before OR", deny, "MIT AND ISC OR Apache-2.0", false},
+		{"parentheses override precedence", deny, "MIT AND (ISC OR Apache-2.0)", true},
+		{"WITH is judged by the license", allow, "GPL-2.0-only WITH Classpath-exception-2.0", false},
+		{"or-later plus matches the bare id", allow, "GPL-2.0-only+", false},
+		{"not allowed", allow, "ISC", true},
+		{"allow list and missing license", allow, "", true},
+		{"allow list and non-SPDX license", allow, "SEE LICENSE IN LICENSE", true},
+		{"deny list ignores non-SPDX license", deny, "SEE LICENSE IN LICENSE", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := tt.policy.Check(tt.license)
+			if v.Violation != tt.want {
+				t.Errorf("Check(%q) violation = %v (%s), want %v", tt.license, v.Violation, v.Reason, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package commands
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// LicenseReportEntry is one installed package in a license report.
+type LicenseReportEntry struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	License string `json:"license"`
+	// Status is "allowed" or "violation"
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+	// Path is the package directory relative to the project root
+	Path string `json:"path"`
+}
+
+// BuildLicenseReport lists every package reachable from the node_modules of
+// the project containing dir, including nested copies and pnpm's linked
+// dependencies, and checks each license against p. Each name@version is
+// reported once.
+func BuildLicenseReport(dir string, p LicensePolicy) ([]LicenseReportEntry, error) {
+	pkgPath := findPackageJSON(dir)
+	if pkgPath == "" {
+		return nil, errors.New("no package.json found")
+	}
+	root := filepath.Dir(pkgPath)
+	nm := filepath.Join(root, "node_modules")
+	if fi, err := os.Stat(nm); err != nil || !fi.IsDir() {
+		return nil, fmt.Errorf("%s not found; install dependencies first", nm)
+	}
+	var out []LicenseReportEntry
+	seenDir := map[string]bool{}
+	seenPkg := map[string]bool{}
+	walkNodeModules(nm, seenDir, func(pkgDir string) {
+		b, err := os.ReadFile(filepath.Join(pkgDir, "package.json"))
+		if err != nil {
+			return
+		}
+		var data struct {
+			Name     string         `json:"name"`
+			Version  string         `json:"version"`
+			License  looseLicense   `json:"license"`
+			Licenses []looseLicense `json:"licenses"`
+		}
+		if json.Unmarshal(b, &data) != nil || data.Name == "" {
+			return
+		}
+		key := data.Name + "@" + data.Version
+		if seenPkg[key] {
+			return
+		}
+		seenPkg[key] = true
+		lic := string(data.License)
+		if lic == "" && len(data.Licenses) > 0 {
+			// legacy "licenses" arrays list alternatives
+			parts := make([]string, 0, len(data.Licenses))
+			for _, l := range data.Licenses {
+				if l != "" {
+					parts = append(parts, string(l))
+				}
+			}
+			lic = strings.Join(parts, " OR ")
+		}
+		e := LicenseReportEntry{Name: data.Name, Version: data.Version, License: lic, Status: "allowed"}
+		if rel, err := filepath.Rel(root, pkgDir); err == nil {
+			e.Path = filepath.ToSlash(rel)
+		}
+		if v := p.Check(lic); v.Violation {
+			e.Status, e.Reason = "violation", v.Reason
+		}
+		out = append(out, e)
+	})
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Name != out[j].Name {
+			return out[i].Name < out[j].Name
+		}
+		return compareVersionStrings(out[i].Version, out[j].Version) < 0
+	})
+	return out, nil
+}
+
+// walkNodeModules calls visit for each package directory in nm, then
+// recurses into its dependencies. Symlinks are resolved and each real
+// directory is visited once. pnpm's .pnpm store is not walked directly, as
+// it can hold versions nothing links to any more.
+func walkNodeModules(nm string, seen map[string]bool, visit func(dir string)) {
+	entries, err := os.ReadDir(nm)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		name := e.Name()
+		switch {
+		case strings.HasPrefix(name, "."):
+			// .pnpm, .bin, .cache, .package-lock.json and friends
+		case strings.HasPrefix(name, "@"):
+			scoped, _ := os.ReadDir(filepath.Join(nm, name))
+			for _, s := range scoped {
+				visitPackageDir(filepath.Join(nm, name, s.Name()), seen, visit)
+			}
+		default:
+			visitPackageDir(filepath.Join(nm, name), seen, visit)
+		}
+	}
+}
+
+// visitPackageDir visits one package directory unless its real path was
+// already seen.
+func visitPackageDir(dir string, seen map[string]bool, visit func(dir string)) {
+	real, err := filepath.EvalSymlinks(dir)
+	if err != nil || seen[real] {
+		return
+	}
+	if fi, err := os.Stat(real); err != nil || !fi.IsDir() {
+		return
+	}
+	seen[real] = true
+	visit(dir)
+	walkNodeModules(filepath.Join(real, "node_modules"), seen, visit)
+	// pnpm links a package's dependencies next to it in the store:
+	// .pnpm/<name>@<version>/node_modules/{<name>,<deps>...}
+	if nm := enclosingNodeModules(real); nm != "" && strings.Contains(filepath.ToSlash(nm), "/.pnpm/") {
+		walkNodeModules(nm, seen, visit)
+	}
+}
+
+// enclosingNodeModules returns the node_modules directory a package
+// directory sits in, looking past a scope directory.
+func enclosingNodeModules(dir string) string {
+	parent := filepath.Dir(dir)
+	if strings.HasPrefix(filepath.Base(parent), "@") {
+		parent = filepath.Dir(parent)
+	}
+	if filepath.Base(parent) != "node_modules" {
+		return ""
+	}
+	return parent
+}
+
+// WriteLicenseReport writes entries to w as "csv" or "json".
+func WriteLicenseReport(w io.Writer, entries []LicenseReportEntry, format string) error {
+	switch strings.ToLower(format) {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if entries == nil {
+			entries = []LicenseReportEntry{}
+		}
+		return enc.Encode(entries)
+	case "csv", "":
+		cw := csv.NewWriter(w)
+		_ = cw.Write([]string{"name", "version", "license", "status", "reason", "path"})
+		for _, e := range entries {
+			_ = cw.Write([]string{e.Name, e.Version, e.License, e.Status, e.Reason, e.Path})
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+	return fmt.Errorf("unknown format %q (want csv or json)", format)
+}
@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuildLicenseReportPnpmLayout(t *testing.T) {
+	root := t.TempDir()
+	write := func(rel, content string) {
+		t.Helper()
+		p := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	link := func(target, rel string) {
+		t.Helper()
+		p := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(filepath.Join(root, target), p); err != nil {
+			t.Skip("symlinks unavailable:", err)
+		}
+	}
+	write("package.json", `{"name":"app"}`)
+	store := "node_modules/.pnpm/"
+	write(store+"a@1.0.0/node_modules/a/package.json", `{"name":"a","version":"1.0.0","license":"MIT"}`)
+	write(store+"@s+b@2.0.0/node_modules/@s/b/package.json", `{"name":"@s/b","version":"2.0.0","license":"GPL-3.0-only"}`)
+	// left behind by an earlier install; nothing links to it
+	write(store+"stale@0.1.0/node_modules/stale/package.json", `{"name":"stale","version":"0.1.0","license":"MIT"}`)
+	link(store+"a@1.0.0/node_modules/a", "node_modules/a")
+	link(store+"@s+b@2.0.0/node_modules/@s/b", store+"a@1.0.0/node_modules/@s/b")
+
+	entries, err := BuildLicenseReport(root, LicensePolicy{Deny: []string{"GPL-3.0-only"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{}
+	for _, e := range entries {
+		got[e.Name+"@"+e.Version] = e.Status
+	}
+	want := map[string]string{"a@1.0.0": "allowed", "@s/b@2.0.0": "violation"}
+	if len(got) != len(want) {
+		t.Fatalf("report = %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s status = %q, want %q", k, got[k], v)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// isMarkdownPath reports whether p names a Markdown file.
+func isMarkdownPath(p string) bool {
+	switch strings.ToLower(path.Ext(p)) {
+	case ".md", ".markdown", ".mdown":
+		return true
+	}
+	return false
+}
+
+// RepoBaseURLs returns the blob (browser) and raw (content) URL prefixes for
+// files on the default branch of repo ("owner/repo"), or empty strings when
+// repo is unknown.
+func RepoBaseURLs(repo string) (blobRoot, rawRoot string) {
+	if repo == "" {
+		return "", ""
+	}
+	return "https://github.com/" + repo + "/blob/HEAD/", "https://raw.githubusercontent.com/" + repo + "/HEAD/"
+}
+
+// UnsupportedLink reports whether href uses a scheme the viewer refuses to
+// open. READMEs are untrusted, so only web and mail links reach the browser;
+// file:, javascript: and custom protocol handlers do not.
+func UnsupportedLink(href string) bool {
+	u, err := url.Parse(strings.TrimSpace(href))
+	if err != nil || u.Scheme == "" {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https", "mailto":
+		return false
+	}
+	return true
+}
+
+// ResolveRepoLink resolves href as found in a document located at dir inside
+// the GitHub repository repo ("owner/repo"). It returns the absolute URL to
+// open in a browser and, when the target is a Markdown file in the same
+// repository, the raw URL to load in the viewer along with its path in the
+// repository. Relative links cannot be resolved without a repository, and
+// links with an unsupported scheme (see UnsupportedLink) resolve to nothing.
+func ResolveRepoLink(repo, dir, href string) (browserURL, rawURL, repoPath string) {
+	href = strings.TrimSpace(href)
+	if href == "" || strings.HasPrefix(href, "#") {
+		return "", "", ""
+	}
+	if UnsupportedLink(href) {
+		return "", "", ""
+	}
+	if u, err := url.Parse(href); err == nil && u.Scheme != "" {
+		if scheme := strings.ToLower(u.Scheme); scheme != "http" && scheme != "https" {
+			// mailto
+			return href, "", ""
+		}
+		// https://github.com/<owner>/<repo>/blob/<ref>/<path>.md in the same repo
+		host := strings.ToLower(u.Host)
+		if repo != "" && (host == "github.com" || host == "www.github.com") {
+			parts := strings.SplitN(strings.Trim(u.Path, "/"), "/", 5)
+			if len(parts) == 5 && strings.EqualFold(parts[0]+"/"+parts[1], repo) && parts[2] == "blob" && isMarkdownPath(parts[4]) {
+				raw := "https://raw.githubusercontent.com/" + parts[0] + "/" + parts[1] + "/" + parts[3] + "/" + parts[4]
+				return href, raw, parts[4]
+			}
+		}
+		return href, "", ""
+	}
+	if repo == "" {
+		return "", "", ""
+	}
+	// Relative link: drop query/fragment and resolve against dir
+	clean := href
+	if i := strings.IndexAny(clean, "?#"); i >= 0 {
+		clean = clean[:i]
+	}
+	var p string
+	if strings.HasPrefix(clean, "/") {
+		p = strings.TrimPrefix(path.Clean(clean), "/")
+	} else {
+		p = strings.TrimPrefix(path.Clean(path.Join("/", dir, clean)), "/")
+	}
+	blobRoot, rawRoot := RepoBaseURLs(repo)
+	browserURL = blobRoot + p
+	if isMarkdownPath(p) {
+		rawURL = rawRoot + p
+	}
+	return browserURL, rawURL, p
+}
+
+// FetchRepoDocument loads a Markdown file from a repository for the README
+// viewer. The returned message keeps repo and the document's directory so
+// relative links inside it resolve correctly.
+func FetchRepoDocument(repo, repoPath, rawURL string, req int) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+		defer cancel()
+		msg := GitHubReadmeMsg{Repo: repo, Dir: path.Dir(repoPath), Filename: path.Base(repoPath), Source: "github", Req: req}
+		if msg.Dir == "." {
+			msg.Dir = ""
+		}
+		r, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+		if err != nil {
+			msg.Err = err
+			return msg
+		}
+		r.Header.Set("User-Agent", "npm-tui (https://github.com/fredrikmwold/npm-tui)")
+		resp, err := http.DefaultClient.Do(r)
+		if err != nil {
+			msg.Err = err
+			return msg
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			msg.Err = errors.New("could not fetch " + repoPath + ": " + resp.Status)
+			return msg
+		}
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			msg.Err = err
+			return msg
+		}
+		msg.Content = cleanReadme(string(b))
+		return msg
+	}
+}
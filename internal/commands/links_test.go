@@ -0,0 +1,38 @@
+package commands
+
+import "testing"
+
+func TestResolveRepoLink(t *testing.T) {
+	const repo = "facebook/react"
+	tests := []struct {
+		dir, href                    string
+		browserURL, rawURL, repoPath string
+		unsupported                  bool
+	}{
+		{"", "", "", "", "", false},
+		{"", "#usage", "", "", "", false},
+		{"", "javascript:alert(1)", "", "", "", true},
+		{"", "JavaScript:alert(1)", "", "", "", true},
+		{"", "file:///etc/passwd", "", "", "", true},
+		{"", "vscode://file/etc/passwd", "", "", "", true},
+		{"", "smb://host/share", "", "", "", true},
+		{"", "mailto:dev@example.com", "mailto:dev@example.com", "", "", false},
+		{"", "https://example.com/x", "https://example.com/x", "", "", false},
+		{"", "https://github.com/facebook/react/blob/main/docs/INSTALL.md",
+			"https://github.com/facebook/react/blob/main/docs/INSTALL.md",
+			"https://raw.githubusercontent.com/facebook/react/main/docs/INSTALL.md", "docs/INSTALL.md", false},
+		{"docs", "../CONTRIBUTING.md#setup",
+			"https://github.com/facebook/react/blob/HEAD/CONTRIBUTING.md",
+			"https://raw.githubusercontent.com/facebook/react/HEAD/CONTRIBUTING.md", "CONTRIBUTING.md", false},
+		{"", "LICENSE", "https://github.com/facebook/react/blob/HEAD/LICENSE", "", "LICENSE", false},
+	}
+	for _, tt := range tests {
+		b, r, p := ResolveRepoLink(repo, tt.dir, tt.href)
+		if b != tt.browserURL || r != tt.rawURL || p != tt.repoPath {
+			t.Errorf("ResolveRepoLink(%q, %q) = %q, %q, %q; want %q, %q, %q", tt.dir, tt.href, b, r, p, tt.browserURL, tt.rawURL, tt.repoPath)
+		}
+		if got := UnsupportedLink(tt.href); got != tt.unsupported {
+			t.Errorf("UnsupportedLink(%q) = %v, want %v", tt.href, got, tt.unsupported)
+		}
+	}
+}
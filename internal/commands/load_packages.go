@@ -3,9 +3,11 @@ package commands
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -59,104 +61,150 @@ func LoadProjectPackages() tea.Cmd {
 		if len(names) == 0 {
 			return NpmSearchMsg{Query: "", Result: NpmSearchResult{Objects: []NpmSearchObject{}}, Err: nil}
 		}
-		client := &http.Client{Timeout: 8 * time.Second}
-		type out struct {
-			idx int
-			obj NpmSearchObject
-		}
-		// pre-size slice
-		result := make([]NpmSearchObject, len(names))
-		sem := make(chan struct{}, 6)
-		done := make(chan out, len(names))
-		for i, nm := range names {
-			i, nm := i, nm
-			sem <- struct{}{}
-			go func() {
-				defer func() { <-sem }()
-				// Try cache first
-				if cached, ok := cacheGetPkg(nm); ok {
-					done <- out{idx: i, obj: cached}
-					return
+		return NpmSearchMsg{Query: "", Result: NpmSearchResult{Objects: loadPackages(names, filepath.Dir(pkgPath))}}
+	}
+}
+
+// loadPackages fetches registry metadata and weekly downloads for names, in
+// order, using the package cache. Installed versions are read from
+// node_modules in baseDir.
+func loadPackages(names []string, baseDir string) []NpmSearchObject {
+	client := &http.Client{Timeout: 8 * time.Second}
+	type out struct {
+		idx int
+		obj NpmSearchObject
+	}
+	// pre-size slice
+	result := make([]NpmSearchObject, len(names))
+	sem := make(chan struct{}, 6)
+	done := make(chan out, len(names))
+	for i, nm := range names {
+		i, nm := i, nm
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem }()
+			// Try cache first
+			if cached, ok := cacheGetPkg(nm); ok {
+				done <- out{idx: i, obj: withInstalled(client, baseDir, cached)}
+				return
+			}
+			// Fetch https://registry.npmjs.com/<name>
+			metaURL := RegistryURL + "/" + url.PathEscape(nm)
+			var obj NpmSearchObject
+			if resp, err := client.Get(metaURL); err == nil && resp != nil {
+				defer resp.Body.Close()
+				body, _ := io.ReadAll(resp.Body)
+				// Keep the typed document for later lookups (e.g., installed version deprecation)
+				var doc packument
+				if err := json.Unmarshal(body, &doc); err == nil {
+					cacheSetPackument(nm, &doc)
 				}
-				// Fetch https://registry.npmjs.com/<name>
-				metaURL := fmt.Sprintf("https://registry.npmjs.com/%s", url.PathEscape(nm))
-				var obj NpmSearchObject
-				if resp, err := client.Get(metaURL); err == nil && resp != nil {
-					defer resp.Body.Close()
-					// We only need latest dist-tags and metadata
-					var raw map[string]any
-					if err := json.NewDecoder(resp.Body).Decode(&raw); err == nil {
-						// Get latest version from dist-tags.latest
-						latest := ""
-						if dt, ok := raw["dist-tags"].(map[string]any); ok {
-							if lv, ok := dt["latest"].(string); ok {
-								latest = lv
-							}
-						}
-						// Switch to versions[latest] for details; fallback to top-level if missing
-						var ver map[string]any
-						if vs, ok := raw["versions"].(map[string]any); ok && latest != "" {
-							if v, ok := vs[latest].(map[string]any); ok {
-								ver = v
-							}
+				// We only need latest dist-tags and metadata
+				var raw map[string]any
+				if err := json.Unmarshal(body, &raw); err == nil {
+					// Get latest version from dist-tags.latest
+					latest := ""
+					if dt, ok := raw["dist-tags"].(map[string]any); ok {
+						if lv, ok := dt["latest"].(string); ok {
+							latest = lv
 						}
-						if ver == nil {
-							ver = raw
-						}
-						// Build NpmPackage
-						var pkg NpmPackage
-						pkg.Name = nm
-						pkg.Version = latest
-						if d, ok := ver["description"].(string); ok {
-							pkg.Description = d
+					}
+					// Switch to versions[latest] for details; fallback to top-level if missing
+					var ver map[string]any
+					if vs, ok := raw["versions"].(map[string]any); ok && latest != "" {
+						if v, ok := vs[latest].(map[string]any); ok {
+							ver = v
 						}
-						if l, ok := ver["license"].(string); ok {
-							pkg.License = l
-						} else if lobj, ok := ver["license"].(map[string]any); ok {
-							if t, ok := lobj["type"].(string); ok {
-								pkg.License = t
-							}
+					}
+					if ver == nil {
+						ver = raw
+					}
+					// Build NpmPackage
+					var pkg NpmPackage
+					pkg.Name = nm
+					pkg.Version = latest
+					if d, ok := ver["description"].(string); ok {
+						pkg.Description = d
+					}
+					if dep, ok := ver["deprecated"].(string); ok {
+						pkg.Deprecated = dep
+					}
+					if l, ok := ver["license"].(string); ok {
+						pkg.License = l
+					} else if lobj, ok := ver["license"].(map[string]any); ok {
+						if t, ok := lobj["type"].(string); ok {
+							pkg.License = t
 						}
-						if a, ok := ver["author"].(map[string]any); ok {
-							if n, ok := a["name"].(string); ok {
-								pkg.Author = n
-							}
-						} else if as, ok := ver["author"].(string); ok {
-							pkg.Author = as
+					}
+					if a, ok := ver["author"].(map[string]any); ok {
+						if n, ok := a["name"].(string); ok {
+							pkg.Author = n
 						}
-						// Links
-						pkg.Links.NPM = fmt.Sprintf("https://www.npmjs.com/package/%s", nm)
-						if lh, ok := ver["homepage"].(string); ok {
-							pkg.Links.Homepage = lh
+					} else if as, ok := ver["author"].(string); ok {
+						pkg.Author = as
+					}
+					// Links
+					pkg.Links.NPM = fmt.Sprintf("https://www.npmjs.com/package/%s", nm)
+					if lh, ok := ver["homepage"].(string); ok {
+						pkg.Links.Homepage = lh
+					}
+					if rep, ok := ver["repository"].(map[string]any); ok {
+						if u, ok := rep["url"].(string); ok {
+							pkg.Links.Repository = u
 						}
-						if rep, ok := ver["repository"].(map[string]any); ok {
-							if u, ok := rep["url"].(string); ok {
-								pkg.Links.Repository = u
-							}
-						} else if rs, ok := ver["repository"].(string); ok {
-							pkg.Links.Repository = rs
+					} else if rs, ok := ver["repository"].(string); ok {
+						pkg.Links.Repository = rs
+					}
+					if bugs, ok := ver["bugs"].(map[string]any); ok {
+						if u, ok := bugs["url"].(string); ok {
+							pkg.Links.Bugs = u
 						}
-						// downloads last week
-						dlURL := "https://api.npmjs.org/downloads/point/last-week/" + url.PathEscape(nm)
-						if r2, e2 := client.Get(dlURL); e2 == nil && r2 != nil {
-							defer r2.Body.Close()
-							var dl downloadsPointResponse
-							if err := json.NewDecoder(r2.Body).Decode(&dl); err == nil {
-								pkg.DownloadsLastWeek = dl.Downloads
-							}
+					} else if bs, ok := ver["bugs"].(string); ok {
+						pkg.Links.Bugs = bs
+					}
+					// downloads last week
+					dlURL := "https://api.npmjs.org/downloads/point/last-week/" + url.PathEscape(nm)
+					if r2, e2 := client.Get(dlURL); e2 == nil && r2 != nil {
+						defer r2.Body.Close()
+						var dl downloadsPointResponse
+						if err := json.NewDecoder(r2.Body).Decode(&dl); err == nil {
+							pkg.DownloadsLastWeek = dl.Downloads
 						}
-						obj = NpmSearchObject{Package: pkg}
 					}
+					obj = NpmSearchObject{Package: pkg}
 				}
-				// Store in cache (even if empty, to avoid tight refetch loops on failures)
-				cacheSetPkg(nm, obj)
-				done <- out{idx: i, obj: obj}
-			}()
-		}
-		for i := 0; i < len(names); i++ {
-			o := <-done
-			result[o.idx] = o.obj
+			}
+			// Store in cache (even if empty, to avoid tight refetch loops on failures)
+			cacheSetPkg(nm, obj)
+			done <- out{idx: i, obj: withInstalled(client, baseDir, obj)}
+		}()
+	}
+	for i := 0; i < len(names); i++ {
+		o := <-done
+		result[o.idx] = o.obj
+	}
+	return result
+}
+
+// withInstalled annotates obj with the version installed in node_modules and
+// whether that version is deprecated. It runs outside the metadata cache since
+// installs change the answer during a session.
+func withInstalled(client *http.Client, baseDir string, obj NpmSearchObject) NpmSearchObject {
+	name := obj.Package.Name
+	iv := installedVersion(baseDir, name)
+	obj.Package.InstalledVersion = iv
+	obj.Package.InstalledDeprecated = ""
+	if iv == "" || name == "" {
+		return obj
+	}
+	if iv == obj.Package.Version {
+		obj.Package.InstalledDeprecated = obj.Package.Deprecated
+		return obj
+	}
+	if p, err := fetchPackument(client, name); err == nil {
+		if v, ok := p.Versions[iv]; ok {
+			obj.Package.InstalledDeprecated = string(v.Deprecated)
 		}
-		return NpmSearchMsg{Query: "", Result: NpmSearchResult{Objects: result}}
 	}
+	return obj
 }
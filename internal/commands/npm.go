@@ -2,27 +2,25 @@ package commands
 
 import (
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"net/url"
-	"time"
-
 	"strconv"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 // SearchNPM issues an HTTP GET to the npm search API asynchronously and
-// returns the parsed results as a tea.Msg.
-func SearchNPM(query string) tea.Cmd {
+// returns up to size parsed results as a tea.Msg.
+func SearchNPM(query string, size int) tea.Cmd {
 	return func() tea.Msg {
 		if query == "" {
 			return NpmSearchMsg{Query: query, Err: nil, Result: NpmSearchResult{}}
 		}
-		u, _ := url.Parse("http://registry.npmjs.com/-/v1/search")
+		u, _ := url.Parse(RegistryURL + "/-/v1/search")
 		q := u.Query()
 		q.Set("text", query)
-		q.Set("size", "10")
+		q.Set("size", strconv.Itoa(size))
 		u.RawQuery = q.Encode()
 
 		client := &http.Client{Timeout: 8 * time.Second}
@@ -40,10 +38,11 @@ func SearchNPM(query string) tea.Cmd {
 
 		// For each package, fetch weekly downloads and augment the result.
 		type result struct {
-			idx       int
-			downloads int
-			license   string
-			author    string
+			idx        int
+			downloads  int
+			license    string
+			author     string
+			deprecated string
 		}
 		sem := make(chan struct{}, 5)                  // limit concurrency
 		done := make(chan result, len(parsed.Objects)) // buffer to avoid deadlock before we start reading
@@ -69,7 +68,8 @@ func SearchNPM(query string) tea.Cmd {
 				// Fetch latest metadata for license
 				lic := ""
 				author := ""
-				latestURL := "https://registry.npmjs.com/" + url.PathEscape(pkg) + "/latest"
+				deprecated := ""
+				latestURL := RegistryURL + "/" + url.PathEscape(pkg) + "/latest"
 				if r2, e2 := client.Get(latestURL); e2 == nil {
 					defer r2.Body.Close()
 					var raw map[string]any
@@ -84,6 +84,9 @@ func SearchNPM(query string) tea.Cmd {
 								}
 							}
 						}
+						if dep, ok := raw["deprecated"].(string); ok {
+							deprecated = dep
+						}
 						if av, ok := raw["author"]; ok {
 							switch t := av.(type) {
 							case string:
@@ -96,7 +99,7 @@ func SearchNPM(query string) tea.Cmd {
 						}
 					}
 				}
-				done <- result{idx: idx, downloads: downloads, license: lic, author: author}
+				done <- result{idx: idx, downloads: downloads, license: lic, author: author, deprecated: deprecated}
 			}(i, name)
 		}
 		// Collect results
@@ -105,6 +108,7 @@ func SearchNPM(query string) tea.Cmd {
 			parsed.Objects[res.idx].Package.DownloadsLastWeek = res.downloads
 			parsed.Objects[res.idx].Package.License = res.license
 			parsed.Objects[res.idx].Package.Author = res.author
+			parsed.Objects[res.idx].Package.Deprecated = res.deprecated
 		}
 
 		// Fill author from publisher username if author unavailable
@@ -117,82 +121,3 @@ func SearchNPM(query string) tea.Cmd {
 		return NpmSearchMsg{Query: query, Result: parsed}
 	}
 }
-
-// FetchDownloadsRange fetches downloads per day for a given package over the last N days
-// using the npm downloads range API and returns an NpmDownloadsRangeMsg.
-// days must be >= 1. Values are ordered oldest..newest.
-func FetchDownloadsRange(pkg string, days int) tea.Cmd {
-	return func() tea.Msg {
-		if pkg == "" || days < 1 {
-			return NpmDownloadsRangeMsg{Package: pkg, Values: nil}
-		}
-		// Check cache first
-		key := pkg + "|" + strconv.Itoa(days)
-		if vals, ok := cacheGetDLRange(key); ok {
-			return NpmDownloadsRangeMsg{Package: pkg, Values: vals}
-		}
-		// Compute date window: inclusive start:end, YYYY-MM-DD
-		end := time.Now().AddDate(0, 0, -1) // yesterday to avoid partial current day
-		start := end.AddDate(0, 0, -(days - 1))
-		startStr := start.Format("2006-01-02")
-		endStr := end.Format("2006-01-02")
-
-		u := "https://api.npmjs.org/downloads/range/" + startStr + ":" + endStr + "/" + url.PathEscape(pkg)
-		client := &http.Client{Timeout: 8 * time.Second}
-		resp, err := client.Get(u)
-		if err != nil {
-			return NpmDownloadsRangeMsg{Package: pkg, Err: err}
-		}
-		defer resp.Body.Close()
-		var parsed rangeDownloadsResponse
-		if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
-			return NpmDownloadsRangeMsg{Package: pkg, Err: err}
-		}
-		// Aggregate to weekly (ISO week) sums to match the "Weekly Downloads" metric
-		// (previously averaged per-day which made the Y-axis appear too low).
-		vals := make([]float64, 0, 64)
-		pts := make([]DownloadPoint, 0, 64)
-		var curWeek string
-		var sum int
-		var count int
-		var weekStart time.Time
-		flush := func() {
-			// Only include full ISO weeks (7 days). This avoids skew from
-			// partial first/last weeks when the requested range doesn't align
-			// to week boundaries.
-			if count == 7 {
-				// Use total weekly downloads (sum) to match the "Weekly Downloads" metric.
-				total := float64(sum)
-				vals = append(vals, total)
-				// place the point at mid-week for better spacing
-				mid := weekStart.AddDate(0, 0, 3)
-				pts = append(pts, DownloadPoint{Time: mid, Value: total})
-			}
-			sum = 0
-			count = 0
-		}
-		for _, d := range parsed.Downloads {
-			t, err := time.Parse("2006-01-02", d.Day)
-			if err != nil {
-				// skip invalid date entries
-				continue
-			}
-			y, w := t.ISOWeek()
-			wk := fmt.Sprintf("%04d-%02d", y, w)
-			if curWeek == "" {
-				curWeek = wk
-				weekStart = t
-			}
-			if wk != curWeek {
-				flush()
-				curWeek = wk
-				weekStart = t
-			}
-			sum += d.Downloads
-			count++
-		}
-		flush()
-		cacheSetDLRange(key, vals)
-		return NpmDownloadsRangeMsg{Package: pkg, Values: vals, Points: pts}
-	}
-}
@@ -0,0 +1,149 @@
+package commands
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// OpenURLMsg reports the result of handing a URL to the system browser.
+type OpenURLMsg struct {
+	URL string
+	Err error
+}
+
+// browserCommand returns the command used to open u: the first entry of
+// $BROWSER found on PATH, otherwise the platform opener (open, xdg-open or
+// rundll32).
+func browserCommand(u string) (*exec.Cmd, error) {
+	for _, args := range browserCandidates(os.Getenv("BROWSER"), u) {
+		if _, err := exec.LookPath(args[0]); err == nil {
+			return exec.Command(args[0], args[1:]...), nil
+		}
+	}
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", u), nil
+	case "windows":
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", u), nil
+	default:
+		if _, err := exec.LookPath("xdg-open"); err != nil {
+			return nil, errors.New("no browser found: set $BROWSER or install xdg-open")
+		}
+		return exec.Command("xdg-open", u), nil
+	}
+}
+
+// browserCandidates parses a $BROWSER value: commands separated like PATH
+// entries, each split into words as a shell would. "%s" in a command is
+// replaced by u and "%%" by "%"; commands without "%s" get u appended.
+// Entries that do not parse are skipped.
+func browserCandidates(env, u string) [][]string {
+	var out [][]string
+	for _, entry := range filepath.SplitList(env) {
+		args, err := shellWords(entry)
+		if err != nil || len(args) == 0 {
+			continue
+		}
+		substituted := false
+		for i, a := range args {
+			if strings.Contains(a, "%s") {
+				substituted = true
+			}
+			args[i] = strings.NewReplacer("%%", "%", "%s", u).Replace(a)
+		}
+		if !substituted {
+			args = append(args, u)
+		}
+		out = append(out, args)
+	}
+	return out
+}
+
+// shellWords splits s into words like a POSIX shell, honouring single and
+// double quotes and backslash escapes. It does not expand anything.
+func shellWords(s string) ([]string, error) {
+	var (
+		words   []string
+		word    strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+	for _, r := range s {
+		switch {
+		case escaped:
+			// Inside double quotes a backslash only escapes a few characters
+			if quote == '"' && !strings.ContainsRune(`"\$`+"`", r) {
+				word.WriteRune('\\')
+			}
+			word.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\\':
+			escaped, inWord = true, true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, errors.New("unterminated quote or escape")
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// Launcher hands a URL to a browser. The app uses SystemLauncher; tests can
+// substitute one that records the URL instead.
+type Launcher func(u string) error
+
+// SystemLauncher opens u in the user's browser without waiting for it to exit.
+func SystemLauncher(u string) error {
+	cmd, err := browserCommand(u)
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	// Reap the process in the background so it does not linger as a zombie
+	go func() { _ = cmd.Wait() }()
+	return nil
+}
+
+// OpenURL opens u with launch, or with SystemLauncher when launch is nil.
+func OpenURL(u string, launch Launcher) tea.Cmd {
+	if launch == nil {
+		launch = SystemLauncher
+	}
+	return func() tea.Msg {
+		return OpenURLMsg{URL: u, Err: launch(u)}
+	}
+}
@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestBrowserCandidates(t *testing.T) {
+	const u = "https://www.npmjs.com/package/react"
+	sep := string(os.PathListSeparator)
+	tests := []struct {
+		env  string
+		want [][]string
+	}{
+		{"", nil},
+		{"firefox", [][]string{{"firefox", u}}},
+		{"firefox --new-tab", [][]string{{"firefox", "--new-tab", u}}},
+		{"chromium --app=%s", [][]string{{"chromium", "--app=" + u}}},
+		{"echo 100%% %s", [][]string{{"echo", "100%", u}}},
+		{"w3m" + sep + "lynx -dump", [][]string{{"w3m", u}, {"lynx", "-dump", u}}},
+		{`'/opt/My Browser/browser' --flag`, [][]string{{"/opt/My Browser/browser", "--flag", u}}},
+		{`"/opt/My Browser/browser" "--title=a \"b\""`, [][]string{{"/opt/My Browser/browser", `--title=a "b"`, u}}},
+		{`/opt/My\ Browser/browser`, [][]string{{"/opt/My Browser/browser", u}}},
+		{"'unterminated" + sep + "lynx", [][]string{{"lynx", u}}},
+		{sep + "  " + sep, nil},
+	}
+	for _, tt := range tests {
+		if got := browserCandidates(tt.env, u); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("browserCandidates(%q) = %q, want %q", tt.env, got, tt.want)
+		}
+	}
+}
+
+func TestOpenURLUsesLauncher(t *testing.T) {
+	var opened []string
+	stub := func(u string) error {
+		opened = append(opened, u)
+		return nil
+	}
+	msg := OpenURL("https://github.com/facebook/react", stub)()
+	if got, ok := msg.(OpenURLMsg); !ok || got.URL != "https://github.com/facebook/react" || got.Err != nil {
+		t.Errorf("OpenURL() message = %#v", msg)
+	}
+	if !reflect.DeepEqual(opened, []string{"https://github.com/facebook/react"}) {
+		t.Errorf("launcher opened %q", opened)
+	}
+}
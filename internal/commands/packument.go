@@ -0,0 +1,289 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+var errNoLatest = errors.New("package has no latest version")
+
+// packument models the subset of the full registry document
+// (https://registry.npmjs.com/<name>) that the UI reads.
+type packument struct {
+	Name        string                      `json:"name"`
+	DistTags    map[string]string           `json:"dist-tags"`
+	Versions    map[string]packumentVersion `json:"versions"`
+	Time        map[string]string           `json:"time"`
+	Maintainers []npmPerson                 `json:"maintainers"`
+	License     looseLicense                `json:"license"`
+	Repository  looseRepository             `json:"repository"`
+	// Readme is only present in the full document, and only for the latest version
+	Readme         string `json:"readme"`
+	ReadmeFilename string `json:"readmeFilename"`
+}
+
+// packumentVersion is the per-version manifest embedded in a packument.
+type packumentVersion struct {
+	Name                 string            `json:"name"`
+	Version              string            `json:"version"`
+	Description          string            `json:"description"`
+	License              looseLicense      `json:"license"`
+	Homepage             string            `json:"homepage"`
+	Repository           looseRepository   `json:"repository"`
+	Types                string            `json:"types"`
+	Typings              string            `json:"typings"`
+	Dependencies         map[string]string `json:"dependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+	Maintainers          []npmPerson       `json:"maintainers"`
+	Deprecated           looseString       `json:"deprecated"`
+	Scripts              map[string]string `json:"scripts"`
+	// NpmUser is the account that published this version
+	NpmUser npmPerson `json:"_npmUser"`
+	Dist    struct {
+		Tarball      string `json:"tarball"`
+		Integrity    string `json:"integrity"`
+		Shasum       string `json:"shasum"`
+		UnpackedSize int64  `json:"unpackedSize"`
+		FileCount    int    `json:"fileCount"`
+		// Attestations is set when the version was published with provenance
+		Attestations *struct {
+			URL        string `json:"url"`
+			Provenance struct {
+				PredicateType string `json:"predicateType"`
+			} `json:"provenance"`
+		} `json:"attestations"`
+		// Signatures are the registry's ECDSA signatures of name@version:integrity
+		Signatures []registrySignature `json:"signatures"`
+	} `json:"dist"`
+}
+
+// npmPerson is a maintainer/author entry as stored by the registry.
+type npmPerson struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// looseLicense accepts both `"MIT"` and the legacy `{ "type": "MIT" }` form.
+type looseLicense string
+
+func (l *looseLicense) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*l = looseLicense(s)
+		return nil
+	}
+	var obj struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(b, &obj); err == nil {
+		*l = looseLicense(obj.Type)
+	}
+	// ignore other shapes (arrays, null) instead of failing the whole document
+	return nil
+}
+
+// looseString accepts a JSON string and treats any other value (e.g. the
+// occasional `"deprecated": false`) as empty.
+type looseString string
+
+func (l *looseString) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*l = looseString(s)
+	}
+	return nil
+}
+
+// looseRepository accepts both `"github:owner/repo"` and `{ "url": "..." }`.
+type looseRepository struct {
+	URL       string
+	Directory string
+}
+
+func (r *looseRepository) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		r.URL = s
+		return nil
+	}
+	var obj struct {
+		URL       string `json:"url"`
+		Directory string `json:"directory"`
+	}
+	if err := json.Unmarshal(b, &obj); err == nil {
+		r.URL = obj.URL
+		r.Directory = obj.Directory
+	}
+	return nil
+}
+
+// latestVersion returns the manifest for dist-tags.latest, if present.
+func (p *packument) latestVersion() (packumentVersion, bool) {
+	if p == nil {
+		return packumentVersion{}, false
+	}
+	latest := p.DistTags["latest"]
+	if latest == "" {
+		return packumentVersion{}, false
+	}
+	v, ok := p.Versions[latest]
+	return v, ok
+}
+
+// publishTime returns the publish timestamp of version v from the time map.
+func (p *packument) publishTime(v string) (time.Time, bool) {
+	if p == nil || p.Time == nil {
+		return time.Time{}, false
+	}
+	s, ok := p.Time[v]
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// fetchPackument downloads the full registry document for name, using the
+// session cache when possible.
+func fetchPackument(client *http.Client, name string) (*packument, error) {
+	return getPackument(client, name, false)
+}
+
+// fetchAbbreviatedPackument downloads the much smaller "corgi" document that
+// npm clients use for installs. It has versions, dependencies and dist info
+// but no time map, maintainers or README.
+func fetchAbbreviatedPackument(client *http.Client, name string) (*packument, error) {
+	return getPackument(client, name, true)
+}
+
+func getPackument(client *http.Client, name string, abbreviated bool) (*packument, error) {
+	key := name
+	if abbreviated {
+		key = "corgi:" + name
+	}
+	if p, ok := cacheGetPackument(key); ok {
+		return p, nil
+	}
+	// A cached full document is a superset of the abbreviated one
+	if abbreviated {
+		if p, ok := cacheGetPackument(name); ok {
+			return p, nil
+		}
+	}
+	// Selecting a package fetches risk signals and publish info at once;
+	// share one download of the (often multi-MB) document between them
+	v, err, _ := packumentFlight.Do(key, func() (any, error) {
+		if p, ok := cacheGetPackument(key); ok {
+			return p, nil
+		}
+		p, err := downloadPackument(client, name, abbreviated)
+		if err != nil {
+			return nil, err
+		}
+		cacheSetPackument(key, p)
+		return p, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*packument), nil
+}
+
+// packumentFlight deduplicates concurrent downloads of the same document,
+// keyed like the cache.
+var packumentFlight singleflight.Group
+
+// downloadPackument requests the full or abbreviated document for name.
+func downloadPackument(client *http.Client, name string, abbreviated bool) (*packument, error) {
+	metaURL := RegistryURL + "/" + url.PathEscape(name)
+	req, err := http.NewRequest(http.MethodGet, metaURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if abbreviated {
+		req.Header.Set("Accept", "application/vnd.npm.install-v1+json")
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("registry returned %s for %s", resp.Status, name)
+	}
+	var p packument
+	if err := json.NewDecoder(resp.Body).Decode(&p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// resolveVersion picks the version of p that satisfies spec: a dist-tag, an
+// exact version or a semver range.
+func (p *packument) resolveVersion(spec string) (packumentVersion, bool) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		spec = "latest"
+	}
+	if tag, ok := p.DistTags[spec]; ok {
+		v, ok := p.Versions[tag]
+		return v, ok
+	}
+	if v, ok := p.Versions[spec]; ok {
+		return v, true
+	}
+	versions := make([]string, 0, len(p.Versions))
+	for k := range p.Versions {
+		versions = append(versions, k)
+	}
+	best, ok := maxSatisfying(versions, spec)
+	if !ok {
+		return packumentVersion{}, false
+	}
+	return p.Versions[best], true
+}
+
+// fetchWeeklyDownloads returns last week's downloads for name (0 on failure).
+func fetchWeeklyDownloads(client *http.Client, name string) int {
+	dlURL := "https://api.npmjs.org/downloads/point/last-week/" + url.PathEscape(name)
+	r, err := client.Get(dlURL)
+	if err != nil || r == nil {
+		return 0
+	}
+	defer r.Body.Close()
+	var dl downloadsPointResponse
+	if err := json.NewDecoder(r.Body).Decode(&dl); err != nil {
+		return 0
+	}
+	return dl.Downloads
+}
+
+// packageExists reports whether the registry knows about name.
+func packageExists(client *http.Client, name string) bool {
+	u := RegistryURL + "/" + url.PathEscape(name) + "/latest"
+	r, err := client.Get(u)
+	if err != nil || r == nil {
+		return false
+	}
+	r.Body.Close()
+	return r.StatusCode == http.StatusOK
+}
+
+// typesPackageName maps a package name to its DefinitelyTyped counterpart,
+// e.g. "lodash" -> "@types/lodash" and "@babel/core" -> "@types/babel__core".
+func typesPackageName(name string) string {
+	if strings.HasPrefix(name, "@") {
+		name = strings.Replace(strings.TrimPrefix(name, "@"), "/", "__", 1)
+	}
+	return "@types/" + name
+}
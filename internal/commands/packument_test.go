@@ -0,0 +1,44 @@
+package commands
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// slowRegistry answers every request with a tiny packument after a delay,
+// counting the requests it receives.
+type slowRegistry struct{ requests atomic.Int32 }
+
+func (r *slowRegistry) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.requests.Add(1)
+	time.Sleep(50 * time.Millisecond)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(`{"name":"flight-test","dist-tags":{"latest":"1.0.0"}}`)),
+		Request:    req,
+	}, nil
+}
+
+func TestGetPackumentSharesConcurrentDownloads(t *testing.T) {
+	reg := &slowRegistry{}
+	client := &http.Client{Transport: reg}
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := fetchPackument(client, "flight-test"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := reg.requests.Load(); n != 1 {
+		t.Errorf("downloaded the packument %d times, want once", n)
+	}
+}
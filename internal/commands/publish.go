@@ -0,0 +1,305 @@
+package commands
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// registryKeysURL lists the public keys npm signs published versions with.
+const registryKeysURL = RegistryURL + "/-/npm/v1/keys"
+
+// PublishInfoMsg describes who maintains a package and how its latest
+// version was published.
+type PublishInfoMsg struct {
+	Package     string
+	Version     string
+	Maintainers []string
+	Publisher   string
+	PublishedAt time.Time
+	// Provenance is set when the version has a provenance attestation;
+	// SourceRepo and SourceCommit come from that attestation when present
+	Provenance   bool
+	SourceRepo   string
+	SourceCommit string
+	// CommitURL links to SourceCommit on the repository host, if known
+	CommitURL string
+	// Builder is the CI system that built the version, e.g. "GitHub Actions"
+	Builder string
+	// Signed is set when the registry signed the version; SignatureVerified
+	// when a signature checked out against the registry's public keys
+	Signed            bool
+	SignatureVerified bool
+	Err               error
+}
+
+// FetchPublishInfo reads maintainers, the publisher and publish date of the
+// latest version of pkg, decodes its provenance attestation (the Sigstore
+// bundle itself is not verified) and verifies its registry signatures.
+func FetchPublishInfo(pkg string) tea.Cmd {
+	return func() tea.Msg {
+		client := &http.Client{Timeout: 8 * time.Second}
+		p, err := fetchPackument(client, pkg)
+		if err != nil {
+			return PublishInfoMsg{Package: pkg, Err: err}
+		}
+		latest, ok := p.latestVersion()
+		if !ok {
+			return PublishInfoMsg{Package: pkg, Err: errNoLatest}
+		}
+		msg := PublishInfoMsg{Package: pkg, Version: latest.Version, Publisher: latest.NpmUser.Name}
+		people := latest.Maintainers
+		if len(people) == 0 {
+			people = p.Maintainers
+		}
+		for _, m := range people {
+			if m.Name != "" {
+				msg.Maintainers = append(msg.Maintainers, m.Name)
+			}
+		}
+		if t, ok := p.publishTime(latest.Version); ok {
+			msg.PublishedAt = t
+		}
+		if a := latest.Dist.Attestations; a != nil && a.Provenance.PredicateType != "" {
+			msg.Provenance = true
+			if a.URL != "" {
+				msg.SourceRepo, msg.SourceCommit, msg.Builder = fetchProvenanceSource(client, a.URL)
+				msg.CommitURL = commitURL(msg.SourceRepo, msg.SourceCommit)
+			}
+		}
+		if len(latest.Dist.Signatures) > 0 {
+			msg.Signed = true
+			payload := latest.Name + "@" + latest.Version + ":" + latest.Dist.Integrity
+			msg.SignatureVerified = verifyRegistrySignatures(client, payload, msg.PublishedAt, latest.Dist.Signatures)
+		}
+		return msg
+	}
+}
+
+// registrySignature is one entry of dist.signatures.
+type registrySignature struct {
+	KeyID string `json:"keyid"`
+	Sig   string `json:"sig"`
+}
+
+// registryKey is a registry signing key. Expires is zero for keys still in
+// use; signatures made after it are not trusted.
+type registryKey struct {
+	pub     *ecdsa.PublicKey
+	expires time.Time
+}
+
+// registryKeys caches the registry's public keys by key id for the session.
+var registryKeys = struct {
+	sync.Mutex
+	m map[string]registryKey
+}{}
+
+// verifyRegistrySignatures reports whether any signature is a valid ECDSA
+// P-256 signature of payload by a registry key that had not expired when the
+// version was published. An unknown publish time only passes unexpired keys.
+func verifyRegistrySignatures(client *http.Client, payload string, publishedAt time.Time, sigs []registrySignature) bool {
+	return verifySignatures(fetchRegistryKeys(client), payload, publishedAt, sigs)
+}
+
+// verifySignatures checks sigs against keys; see verifyRegistrySignatures.
+func verifySignatures(keys map[string]registryKey, payload string, publishedAt time.Time, sigs []registrySignature) bool {
+	digest := sha256.Sum256([]byte(payload))
+	for _, s := range sigs {
+		key, ok := keys[s.KeyID]
+		if !ok {
+			continue
+		}
+		if !key.expires.IsZero() && (publishedAt.IsZero() || publishedAt.After(key.expires)) {
+			continue
+		}
+		der, err := base64.StdEncoding.DecodeString(s.Sig)
+		if err != nil {
+			continue
+		}
+		if ecdsa.VerifyASN1(key.pub, digest[:], der) {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchRegistryKeys downloads the registry signing keys once per session.
+func fetchRegistryKeys(client *http.Client) map[string]registryKey {
+	registryKeys.Lock()
+	defer registryKeys.Unlock()
+	if registryKeys.m != nil {
+		return registryKeys.m
+	}
+	resp, err := client.Get(registryKeysURL)
+	if err != nil {
+		return nil
+	}
+	defer resp.Body.Close()
+	var body struct {
+		Keys []struct {
+			KeyID string `json:"keyid"`
+			Key   string `json:"key"`
+			// Expires is null for keys still in use
+			Expires *time.Time `json:"expires"`
+		} `json:"keys"`
+	}
+	if resp.StatusCode != http.StatusOK || json.NewDecoder(resp.Body).Decode(&body) != nil {
+		return nil
+	}
+	keys := map[string]registryKey{}
+	for _, k := range body.Keys {
+		der, err := base64.StdEncoding.DecodeString(k.Key)
+		if err != nil {
+			continue
+		}
+		pub, err := x509.ParsePKIXPublicKey(der)
+		if err != nil {
+			continue
+		}
+		if ec, ok := pub.(*ecdsa.PublicKey); ok {
+			key := registryKey{pub: ec}
+			if k.Expires != nil {
+				key.expires = *k.Expires
+			}
+			keys[k.KeyID] = key
+		}
+	}
+	registryKeys.m = keys
+	return keys
+}
+
+// fetchProvenanceSource downloads the attestation bundle at u and returns
+// the source repository, commit and builder named in its SLSA provenance.
+func fetchProvenanceSource(client *http.Client, u string) (repo, commit, builder string) {
+	resp, err := client.Get(u)
+	if err != nil {
+		return "", "", ""
+	}
+	defer resp.Body.Close()
+	var body struct {
+		Attestations []struct {
+			PredicateType string `json:"predicateType"`
+			Bundle        struct {
+				DSSEEnvelope struct {
+					Payload string `json:"payload"`
+				} `json:"dsseEnvelope"`
+			} `json:"bundle"`
+		} `json:"attestations"`
+	}
+	if resp.StatusCode != http.StatusOK || json.NewDecoder(resp.Body).Decode(&body) != nil {
+		return "", "", ""
+	}
+	for _, a := range body.Attestations {
+		if !strings.HasPrefix(a.PredicateType, "https://slsa.dev/provenance/") {
+			continue
+		}
+		raw, err := base64.StdEncoding.DecodeString(a.Bundle.DSSEEnvelope.Payload)
+		if err != nil {
+			continue
+		}
+		if repo, commit, builder = parseProvenance(raw); repo != "" || commit != "" {
+			return repo, commit, builder
+		}
+	}
+	return "", "", ""
+}
+
+// parseProvenance extracts the source from an in-toto statement carrying a
+// SLSA v1 (resolvedDependencies) or v0.2 (materials) provenance predicate.
+func parseProvenance(statement []byte) (repo, commit, builder string) {
+	var st struct {
+		Predicate struct {
+			BuildDefinition struct {
+				ResolvedDependencies []provenanceSource `json:"resolvedDependencies"`
+			} `json:"buildDefinition"`
+			RunDetails struct {
+				Builder struct {
+					ID string `json:"id"`
+				} `json:"builder"`
+			} `json:"runDetails"`
+			Materials []provenanceSource `json:"materials"`
+			Builder   struct {
+				ID string `json:"id"`
+			} `json:"builder"`
+		} `json:"predicate"`
+	}
+	if json.Unmarshal(statement, &st) != nil {
+		return "", "", ""
+	}
+	builderID := st.Predicate.RunDetails.Builder.ID
+	if builderID == "" {
+		builderID = st.Predicate.Builder.ID
+	}
+	builder = builderName(builderID)
+	sources := append(st.Predicate.BuildDefinition.ResolvedDependencies, st.Predicate.Materials...)
+	for _, s := range sources {
+		c := s.Digest["gitCommit"]
+		if c == "" {
+			c = s.Digest["sha1"]
+		}
+		if c == "" || s.URI == "" {
+			continue
+		}
+		return sourceRepoURL(s.URI), c, builder
+	}
+	return "", "", builder
+}
+
+// provenanceSource is a source or material entry of a provenance predicate.
+type provenanceSource struct {
+	URI    string            `json:"uri"`
+	Digest map[string]string `json:"digest"`
+}
+
+// sourceRepoURL turns "git+https://github.com/o/r@refs/heads/main" into
+// "https://github.com/o/r".
+func sourceRepoURL(uri string) string {
+	uri = strings.TrimPrefix(uri, "git+")
+	// the ref is an @ after the host; one before it is ssh user info
+	path := 0
+	if s := strings.Index(uri, "://"); s >= 0 {
+		if h := strings.Index(uri[s+3:], "/"); h >= 0 {
+			path = s + 3 + h
+		}
+	}
+	if i := strings.LastIndex(uri, "@"); i > path && path > 0 {
+		uri = uri[:i]
+	}
+	return strings.TrimSuffix(uri, ".git")
+}
+
+// builderName maps well-known builder ids to a display name.
+func builderName(id string) string {
+	switch {
+	case id == "":
+		return ""
+	case strings.Contains(id, "github.com/"):
+		return "GitHub Actions"
+	case strings.Contains(id, "gitlab"):
+		return "GitLab CI"
+	}
+	return id
+}
+
+// commitURL links to commit in repo for GitHub and GitLab repositories.
+func commitURL(repo, commit string) string {
+	if repo == "" || commit == "" {
+		return ""
+	}
+	switch {
+	case strings.HasPrefix(repo, "https://github.com/"):
+		return repo + "/commit/" + commit
+	case strings.HasPrefix(repo, "https://gitlab.com/"):
+		return repo + "/-/commit/" + commit
+	}
+	return ""
+}
@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+func TestVerifySignatures(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := "react@18.3.1:sha512-abc"
+	digest := sha256.Sum256([]byte(payload))
+	der, err := ecdsa.SignASN1(rand.Reader, priv, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigs := []registrySignature{{KeyID: "k1", Sig: base64.StdEncoding.EncodeToString(der)}}
+	expires := time.Date(2025, 1, 29, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		key         registryKey
+		payload     string
+		publishedAt time.Time
+		want        bool
+	}{
+		{"active key", registryKey{pub: &priv.PublicKey}, payload, expires.AddDate(1, 0, 0), true},
+		{"signed before expiry", registryKey{pub: &priv.PublicKey, expires: expires}, payload, expires.AddDate(0, -1, 0), true},
+		{"signed after expiry", registryKey{pub: &priv.PublicKey, expires: expires}, payload, expires.AddDate(0, 0, 1), false},
+		{"expired key with unknown publish time", registryKey{pub: &priv.PublicKey, expires: expires}, payload, time.Time{}, false},
+		{"tampered payload", registryKey{pub: &priv.PublicKey}, payload + "x", expires, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := map[string]registryKey{"k1": tt.key}
+			if got := verifySignatures(keys, tt.payload, tt.publishedAt, sigs); got != tt.want {
+				t.Errorf("verifySignatures() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -12,35 +12,104 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-// GitHubReadmeMsg is emitted when fetching a README from GitHub completes.
+// GitHubReadmeMsg is emitted when fetching a README completes, whether it
+// came from the registry, the tarball or GitHub.
 type GitHubReadmeMsg struct {
 	Repo    string // owner/repo
 	Content string // decoded markdown
-	Err     error
-	Req     int // request sequence
+	// Dir is the document's directory inside Repo, used to resolve relative links
+	Dir string
+	// Filename is the README's file name when known (e.g. README.rst)
+	Filename string
+	// Source describes where the README came from: registry, tarball or github
+	Source string
+	Err    error
+	Req    int // request sequence
 }
 
+// noReadmeData is the placeholder the registry stores when a package has no README.
+const noReadmeData = "ERROR: No README data found!"
+
+// FetchReadme loads a package README from the packument's readme field, then
+// from the README in the published tarball, and finally from GitHub using the
+// repository URL (honouring repository.directory for monorepo packages).
+func FetchReadme(pkg, repoURL string, req int) tea.Cmd {
+	return func() tea.Msg {
+		client := &http.Client{Timeout: 8 * time.Second}
+		p, err := fetchPackument(client, pkg)
+		if err != nil {
+			return fetchGitHubReadme(repoURL, "", req)
+		}
+		latest, hasLatest := p.latestVersion()
+		dir := p.Repository.Directory
+		if hasLatest {
+			if repoURL == "" {
+				repoURL = latest.Repository.URL
+			}
+			if latest.Repository.Directory != "" {
+				dir = latest.Repository.Directory
+			}
+		}
+		if repoURL == "" {
+			repoURL = p.Repository.URL
+		}
+		// Keep the repository so relative links in registry/tarball READMEs resolve
+		slug := ""
+		if owner, repo, err := parseGitHubRepo(repoURL); err == nil {
+			slug = owner + "/" + repo
+		}
+		if md := strings.TrimSpace(p.Readme); md != "" && md != noReadmeData {
+			return GitHubReadmeMsg{Repo: slug, Dir: dir, Content: cleanReadme(p.Readme), Filename: p.ReadmeFilename, Source: "registry", Req: req}
+		}
+		if hasLatest && latest.Dist.Tarball != "" {
+			// Shares the verified tarball cache with the tarball browser
+			tarballClient := &http.Client{Timeout: 30 * time.Second}
+			if archive, err := cachedTarball(tarballClient, pkg, latest); err == nil {
+				name, b, err := readArchiveFile(archive, isRootReadme)
+				if err == nil && len(b) > 0 {
+					return GitHubReadmeMsg{Repo: slug, Dir: dir, Content: cleanReadme(string(b)), Filename: name, Source: "tarball", Req: req}
+				}
+			}
+		}
+		return fetchGitHubReadme(repoURL, dir, req)
+	}
+}
+
+// isRootReadme matches README files at the package root (README, README.md, readme.rst, ...).
+func isRootReadme(path string) bool {
+	if strings.Contains(path, "/") {
+		return false
+	}
+	base := strings.ToLower(path)
+	return base == "readme" || strings.HasPrefix(base, "readme.")
+}
+
+// cleanReadme trims a potential BOM and leading whitespace/newlines to avoid ghost lines.
+func cleanReadme(md string) string { return strings.TrimLeft(md, "\ufeff\n\r\t ") }
+
 // FetchGitHubReadme fetches the README for a GitHub repo URL and returns the markdown.
 // Accepts repository URLs like:
 // - https://github.com/owner/repo
 // - git+https://github.com/owner/repo.git
 // - git@github.com:owner/repo.git
 func FetchGitHubReadmeWithReq(repoURL string, req int) tea.Cmd {
-	return func() tea.Msg {
-		owner, repo, err := parseGitHubRepo(repoURL)
-		if err != nil {
-			return GitHubReadmeMsg{Repo: "", Content: "", Err: err, Req: req}
-		}
-		// Only use raw.githubusercontent.com fallbacks.
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		if md := tryRawFallbackCtx(ctx, owner, repo); md != "" {
-			// Trim a potential BOM and leading whitespace/newlines to avoid ghost lines
-			md = strings.TrimLeft(md, "\ufeff\n\r\t ")
-			return GitHubReadmeMsg{Repo: owner + "/" + repo, Content: md, Req: req}
-		}
-		return GitHubReadmeMsg{Repo: owner + "/" + repo, Err: errors.New("could not fetch README"), Req: req}
+	return func() tea.Msg { return fetchGitHubReadme(repoURL, "", req) }
+}
+
+// fetchGitHubReadme tries raw README locations in the repository, looking in
+// dir first when the package lives in a monorepo subdirectory.
+func fetchGitHubReadme(repoURL, dir string, req int) GitHubReadmeMsg {
+	owner, repo, err := parseGitHubRepo(repoURL)
+	if err != nil {
+		return GitHubReadmeMsg{Repo: "", Content: "", Err: err, Req: req}
+	}
+	// Only use raw.githubusercontent.com fallbacks.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if name, md, found := tryRawFilesInCtx(ctx, owner, repo, dir, readmeNames); md != "" {
+		return GitHubReadmeMsg{Repo: owner + "/" + repo, Dir: found, Content: cleanReadme(md), Filename: name, Source: "github", Req: req}
 	}
+	return GitHubReadmeMsg{Repo: owner + "/" + repo, Err: errors.New("could not fetch README"), Req: req}
 }
 
 // Backwards-compatible wrapper without sequence
@@ -107,42 +176,57 @@ func parseGitHubRepo(s string) (owner, repo string, err error) {
 // tryRawFallback attempts common raw README locations to bypass API rate limits.
 // tryRawFallback remains as context-less helper if needed elsewhere (not used now)
 
+// readmeNames are the README file names tried in the repository.
+var readmeNames = []string{
+	"README.md", "Readme.md", "readme.md",
+	"README.MD", "README.markdown", "README.rst", "README.txt", "README",
+}
+
 // Context-aware variant used in the command flow
 func tryRawFallbackCtx(ctx context.Context, owner, repo string) string {
+	_, md := tryRawFilesCtx(ctx, owner, repo, "", readmeNames)
+	return md
+}
+
+// tryRawFilesCtx returns the name and content of the first of names found on
+// the default branch (HEAD), main or master. When dir is set (monorepo
+// packages) it is tried before the repository root.
+func tryRawFilesCtx(ctx context.Context, owner, repo, dir string, names []string) (string, string) {
+	name, content, _ := tryRawFilesInCtx(ctx, owner, repo, dir, names)
+	return name, content
+}
+
+// tryRawFilesInCtx is tryRawFilesCtx that also reports the directory the file
+// was found in ("" for the repository root).
+func tryRawFilesInCtx(ctx context.Context, owner, repo, dir string, names []string) (string, string, string) {
 	client := &http.Client{Timeout: 3 * time.Second}
-	branches := []string{"main", "master"}
-	names := []string{
-		"README.md", "Readme.md", "readme.md",
-		"README.MD", "README.markdown", "README.rst", "README.txt", "README",
+	branches := []string{"HEAD", "main", "master"}
+	prefixes := []string{""}
+	if dir = strings.Trim(dir, "/"); dir != "" {
+		prefixes = []string{dir + "/", ""}
 	}
 	for _, br := range branches {
-		for _, nm := range names {
-			u := "https://raw.githubusercontent.com/" + url.PathEscape(owner) + "/" + url.PathEscape(repo) + "/" + url.PathEscape(br) + "/" + nm
-			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
-			req.Header.Set("User-Agent", "npm-tui (https://github.com/fredrikmwold/npm-tui)")
-			start := time.Now()
-			if r, err := client.Do(req); err == nil && r != nil {
-				if r.StatusCode == http.StatusOK {
-					b, _ := io.ReadAll(r.Body)
-					r.Body.Close()
-					if len(b) > 0 {
-						return string(b)
+		for _, pre := range prefixes {
+			for _, nm := range names {
+				u := "https://raw.githubusercontent.com/" + url.PathEscape(owner) + "/" + url.PathEscape(repo) + "/" + url.PathEscape(br) + "/" + pre + nm
+				req, _ := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+				req.Header.Set("User-Agent", "npm-tui (https://github.com/fredrikmwold/npm-tui)")
+				if r, err := client.Do(req); err == nil && r != nil {
+					if r.StatusCode == http.StatusOK {
+						b, _ := io.ReadAll(r.Body)
+						r.Body.Close()
+						if len(b) > 0 {
+							return nm, string(b), strings.TrimSuffix(pre, "/")
+						}
 					} else {
-						// empty body
+						r.Body.Close()
 					}
-				} else {
-					// Read a small snippet for diagnostics (discarded)
-					snip, _ := io.ReadAll(io.LimitReader(r.Body, 256))
-					r.Body.Close()
-					_ = snip
 				}
-			} else if err != nil {
-				_ = time.Since(start)
-			}
-			if ctx.Err() != nil {
-				return ""
+				if ctx.Err() != nil {
+					return "", "", ""
+				}
 			}
 		}
 	}
-	return ""
+	return "", "", ""
 }
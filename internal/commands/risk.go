@@ -0,0 +1,101 @@
+package commands
+
+import (
+	"net/http"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// recentPublishWindow is how long after publishing a version counts as "new".
+// Most malicious releases are caught and unpublished within this window.
+const recentPublishWindow = 72 * time.Hour
+
+// installLifecycleScripts are the scripts npm runs automatically on install.
+var installLifecycleScripts = []string{"preinstall", "install", "postinstall"}
+
+// RiskMsg carries supply-chain risk signals for the latest version of a package.
+type RiskMsg struct {
+	Package string
+	Version string
+	// InstallScripts maps lifecycle script names that run on install to their commands
+	InstallScripts map[string]string
+	// Publisher is the account that published the version; NewPublisher is set
+	// when it never published an earlier version
+	Publisher    string
+	NewPublisher bool
+	PublishedAt  time.Time
+	// Recent is set when the version is younger than recentPublishWindow
+	Recent bool
+	// Provenance is set when the version has a provenance attestation; the
+	// attestation itself is not verified
+	Provenance bool
+	Err        error
+}
+
+// Risky reports whether any signal warrants confirmation before installing.
+// Missing provenance alone is informational: most packages do not have it yet.
+func (r RiskMsg) Risky() bool {
+	return r.Err == nil && (len(r.InstallScripts) > 0 || r.NewPublisher || r.Recent)
+}
+
+// FetchRiskSignals inspects the latest version of pkg for install scripts, a
+// first-time publisher, a very recent publish date and provenance.
+func FetchRiskSignals(pkg string) tea.Cmd {
+	return func() tea.Msg {
+		client := &http.Client{Timeout: 8 * time.Second}
+		p, err := fetchPackument(client, pkg)
+		if err != nil {
+			return RiskMsg{Package: pkg, Err: err}
+		}
+		latest, ok := p.latestVersion()
+		if !ok {
+			return RiskMsg{Package: pkg, Err: errNoLatest}
+		}
+		msg := RiskMsg{Package: pkg, Version: latest.Version, Publisher: latest.NpmUser.Name}
+		for _, s := range installLifecycleScripts {
+			if cmd, ok := latest.Scripts[s]; ok && cmd != "" {
+				if msg.InstallScripts == nil {
+					msg.InstallScripts = map[string]string{}
+				}
+				msg.InstallScripts[s] = cmd
+			}
+		}
+		if t, ok := p.publishTime(latest.Version); ok {
+			msg.PublishedAt = t
+			msg.Recent = time.Since(t) < recentPublishWindow
+		}
+		msg.Provenance = latest.Dist.Attestations != nil && latest.Dist.Attestations.Provenance.PredicateType != ""
+		msg.NewPublisher = isNewPublisher(p, latest)
+		return msg
+	}
+}
+
+// isNewPublisher reports whether latest was published by an account that did
+// not publish any earlier version. First releases and versions without
+// publisher data are not flagged.
+func isNewPublisher(p *packument, latest packumentVersion) bool {
+	if latest.NpmUser.Name == "" {
+		return false
+	}
+	latestAt, hasTime := p.publishTime(latest.Version)
+	known := false
+	for v, pv := range p.Versions {
+		if v == latest.Version || pv.NpmUser.Name == "" {
+			continue
+		}
+		// Compare by publish time when known so backported patches count as earlier
+		if t, ok := p.publishTime(v); ok && hasTime {
+			if !t.Before(latestAt) {
+				continue
+			}
+		} else if compareVersionStrings(v, latest.Version) >= 0 {
+			continue
+		}
+		known = true
+		if pv.NpmUser.Name == latest.NpmUser.Name {
+			return false
+		}
+	}
+	return known
+}
@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestIsNewPublisher(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want bool
+	}{
+		{
+			name: "same publisher as before",
+			doc: `{"dist-tags":{"latest":"1.1.0"},
+				"versions":{"1.0.0":{"version":"1.0.0","_npmUser":{"name":"alice"}},
+				            "1.1.0":{"version":"1.1.0","_npmUser":{"name":"alice"}}}}`,
+		},
+		{
+			name: "publisher never published before",
+			doc: `{"dist-tags":{"latest":"1.1.0"},
+				"versions":{"1.0.0":{"version":"1.0.0","_npmUser":{"name":"alice"}},
+				            "1.1.0":{"version":"1.1.0","_npmUser":{"name":"mallory"}}}}`,
+			want: true,
+		},
+		{
+			name: "first release",
+			doc: `{"dist-tags":{"latest":"1.0.0"},
+				"versions":{"1.0.0":{"version":"1.0.0","_npmUser":{"name":"alice"}}}}`,
+		},
+		{
+			name: "publisher only released a later backport",
+			doc: `{"dist-tags":{"latest":"2.0.0"},
+				"versions":{"1.0.0":{"version":"1.0.0","_npmUser":{"name":"alice"}},
+				            "1.0.1":{"version":"1.0.1","_npmUser":{"name":"bob"}},
+				            "2.0.0":{"version":"2.0.0","_npmUser":{"name":"bob"}}},
+				"time":{"1.0.0":"2024-01-01T00:00:00Z","2.0.0":"2024-02-01T00:00:00Z","1.0.1":"2024-03-01T00:00:00Z"}}`,
+			want: true,
+		},
+		{
+			name: "no publisher data",
+			doc: `{"dist-tags":{"latest":"1.1.0"},
+				"versions":{"1.0.0":{"version":"1.0.0","_npmUser":{"name":"alice"}},
+				            "1.1.0":{"version":"1.1.0"}}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p packument
+			if err := json.Unmarshal([]byte(tt.doc), &p); err != nil {
+				t.Fatal(err)
+			}
+			latest, ok := p.latestVersion()
+			if !ok {
+				t.Fatal("no latest version")
+			}
+			if got := isNewPublisher(&p, latest); got != tt.want {
+				t.Errorf("isNewPublisher() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRiskMsgRisky(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  RiskMsg
+		want bool
+	}{
+		{"nothing", RiskMsg{}, false},
+		{"missing provenance alone", RiskMsg{Provenance: false}, false},
+		{"install scripts", RiskMsg{InstallScripts: map[string]string{"postinstall": "node x.js"}}, true},
+		{"new publisher", RiskMsg{NewPublisher: true}, true},
+		{"recent", RiskMsg{Recent: true}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.msg.Risky(); got != tt.want {
+			t.Errorf("%s: Risky() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
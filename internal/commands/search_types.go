@@ -7,6 +7,8 @@ type NpmSearchMsg struct {
 	Query  string
 	Result NpmSearchResult
 	Err    error
+	// Watchlist is set when Result holds the watched packages
+	Watchlist bool
 }
 
 // NpmSearchResult models the subset of the npm search payload we care about
@@ -45,6 +47,12 @@ type NpmPackage struct {
 	DownloadsLastWeek int    `json:"-"`
 	License           string `json:"-"`
 	Author            string `json:"-"`
+	// Deprecated is the registry deprecation message of the latest version
+	Deprecated string `json:"-"`
+	// InstalledVersion is read from node_modules for project packages
+	InstalledVersion string `json:"-"`
+	// InstalledDeprecated is the deprecation message of the installed version
+	InstalledDeprecated string `json:"-"`
 }
 
 // NpmScore mirrors the score field in search API
@@ -68,10 +76,12 @@ type downloadsPointResponse struct {
 // NpmDownloadsRangeMsg carries downloads-over-time values for a package.
 // Values are ordered from oldest to newest.
 type NpmDownloadsRangeMsg struct {
-	Package string
-	Values  []float64
-	Points  []DownloadPoint
-	Err     error
+	Package     string
+	Window      DownloadsWindow
+	Granularity Granularity
+	Values      []float64
+	Points      []DownloadPoint
+	Err         error
 }
 
 // rangeDownloadsResponse models the /downloads/range API response
@@ -0,0 +1,306 @@
+package commands
+
+import (
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// semver.go: a small subset of node-semver, enough to resolve dependency
+// ranges against registry versions and to order versions.
+
+// semver is a parsed MAJOR.MINOR.PATCH[-PRERELEASE] version.
+type semver struct {
+	major, minor, patch int
+	pre                 string
+}
+
+// parseSemver parses a full version, tolerating a leading "v"/"=" and
+// ignoring build metadata.
+func parseSemver(s string) (semver, bool) {
+	parts, n, pre, ok := parsePartial(s)
+	if !ok || n != 3 {
+		return semver{}, false
+	}
+	return semver{major: parts[0], minor: parts[1], patch: parts[2], pre: pre}, true
+}
+
+// parsePartial parses a possibly partial version such as "1", "1.2", "1.x"
+// or "1.2.3-beta.1". n is the number of specified numeric parts; wildcards
+// (x, X, *) end the version.
+func parsePartial(s string) (parts [3]int, n int, pre string, ok bool) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimLeft(s, "=vV")
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i]
+	}
+	if s == "" || s == "*" || s == "x" || s == "X" {
+		return parts, 0, "", true
+	}
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		pre = s[i+1:]
+		s = s[:i]
+	}
+	for i, f := range strings.Split(s, ".") {
+		if i >= 3 {
+			return parts, 0, "", false
+		}
+		if f == "x" || f == "X" || f == "*" {
+			return parts, n, "", true
+		}
+		v, err := strconv.Atoi(f)
+		if err != nil || v < 0 {
+			return parts, 0, "", false
+		}
+		parts[i] = v
+		n++
+	}
+	return parts, n, pre, true
+}
+
+// compareSemver returns -1, 0 or 1 following semver precedence.
+func compareSemver(a, b semver) int {
+	for _, d := range [][2]int{{a.major, b.major}, {a.minor, b.minor}, {a.patch, b.patch}} {
+		if d[0] != d[1] {
+			if d[0] < d[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return comparePrerelease(a.pre, b.pre)
+}
+
+func comparePrerelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1 // release > prerelease
+	case b == "":
+		return -1
+	}
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		ai, aerr := strconv.Atoi(as[i])
+		bi, berr := strconv.Atoi(bs[i])
+		switch {
+		case aerr == nil && berr == nil:
+			if ai != bi {
+				if ai < bi {
+					return -1
+				}
+				return 1
+			}
+		case aerr == nil:
+			return -1 // numeric identifiers sort first
+		case berr == nil:
+			return 1
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	switch {
+	case len(as) < len(bs):
+		return -1
+	case len(as) > len(bs):
+		return 1
+	}
+	return 0
+}
+
+// compareVersionStrings orders two version strings; unparsable versions sort first.
+func compareVersionStrings(a, b string) int {
+	va, oka := parseSemver(a)
+	vb, okb := parseSemver(b)
+	switch {
+	case oka && okb:
+		return compareSemver(va, vb)
+	case oka:
+		return 1
+	case okb:
+		return -1
+	}
+	return strings.Compare(a, b)
+}
+
+// sortVersions sorts version strings ascending by semver precedence.
+func sortVersions(vs []string) {
+	sort.SliceStable(vs, func(i, j int) bool { return compareVersionStrings(vs[i], vs[j]) < 0 })
+}
+
+// comparator tests a version against one bound.
+type comparator func(semver) bool
+
+// satisfies reports whether version v matches the npm range spec.
+// Like node-semver, a prerelease only matches a comparator set in which a
+// comparator carries a prerelease of the same MAJOR.MINOR.PATCH, so
+// ">=1.2.3-beta.1" admits 1.2.3-beta.2 but not 1.2.4-beta.1.
+func satisfies(v semver, spec string) bool {
+	for _, set := range strings.Split(spec, "||") {
+		cmps, pres, ok := parseComparatorSet(set)
+		if !ok {
+			continue
+		}
+		if v.pre != "" && !slices.ContainsFunc(pres, func(p semver) bool {
+			return p.major == v.major && p.minor == v.minor && p.patch == v.patch
+		}) {
+			continue
+		}
+		match := true
+		for _, c := range cmps {
+			if !c(v) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// maxSatisfying returns the highest version in versions matching spec.
+func maxSatisfying(versions []string, spec string) (string, bool) {
+	best := ""
+	var bestV semver
+	for _, s := range versions {
+		v, ok := parseSemver(s)
+		if !ok || !satisfies(v, spec) {
+			continue
+		}
+		if best == "" || compareSemver(v, bestV) > 0 {
+			best, bestV = s, v
+		}
+	}
+	return best, best != ""
+}
+
+// parseComparatorSet parses a whitespace-separated set such as ">=1.2 <2",
+// "^1.2.3", "1.2.x" or a hyphen range "1.2 - 2.3". pres lists the versions
+// in the set that carry a prerelease tag.
+func parseComparatorSet(set string) (cmps []comparator, pres []semver, ok bool) {
+	set = strings.TrimSpace(set)
+	if set == "" || set == "*" || set == "latest" {
+		return []comparator{func(semver) bool { return true }}, nil, true
+	}
+	if i := strings.Index(set, " - "); i >= 0 {
+		lo, hi := strings.TrimSpace(set[:i]), strings.TrimSpace(set[i+3:])
+		loCmps, lok := parseBound(">=", lo)
+		hiCmps, hok := parseBound("<=", hi)
+		if !lok || !hok {
+			return nil, nil, false
+		}
+		return append(loCmps, hiCmps...), prereleases(lo, hi), true
+	}
+	// Join operators separated from their version by a space (">= 1.2")
+	fields := strings.Fields(set)
+	var toks []string
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+		if strings.Trim(f, "<>=~^") == "" && i+1 < len(fields) {
+			f += fields[i+1]
+			i++
+		}
+		toks = append(toks, f)
+	}
+	for _, t := range toks {
+		op := ""
+		for _, p := range []string{">=", "<=", ">", "<", "=", "^", "~>", "~"} {
+			if strings.HasPrefix(t, p) {
+				op = p
+				t = t[len(p):]
+				break
+			}
+		}
+		cs, ok := parseBound(op, t)
+		if !ok {
+			return nil, nil, false
+		}
+		cmps = append(cmps, cs...)
+		pres = append(pres, prereleases(t)...)
+	}
+	return cmps, pres, true
+}
+
+// prereleases returns the full versions among vers that have a prerelease tag.
+func prereleases(vers ...string) []semver {
+	var out []semver
+	for _, ver := range vers {
+		if v, ok := parseSemver(ver); ok && v.pre != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// parseBound desugars a single operator and partial version into bounds.
+func parseBound(op, ver string) ([]comparator, bool) {
+	p, n, pre, ok := parsePartial(ver)
+	if !ok {
+		return nil, false
+	}
+	at := func(maj, min, pat int, pr string) semver { return semver{major: maj, minor: min, patch: pat, pre: pr} }
+	lower := at(p[0], p[1], p[2], pre)
+	ge := func(b semver) comparator { return func(v semver) bool { return compareSemver(v, b) >= 0 } }
+	gt := func(b semver) comparator { return func(v semver) bool { return compareSemver(v, b) > 0 } }
+	lt := func(b semver) comparator { return func(v semver) bool { return compareSemver(v, b) < 0 } }
+	le := func(b semver) comparator { return func(v semver) bool { return compareSemver(v, b) <= 0 } }
+	// next returns the exclusive upper bound for a partial version
+	next := func() semver {
+		switch n {
+		case 1:
+			return at(p[0]+1, 0, 0, "0")
+		case 2:
+			return at(p[0], p[1]+1, 0, "0")
+		}
+		return at(p[0], p[1], p[2]+1, "0")
+	}
+	if n == 0 {
+		switch op {
+		case "<", ">":
+			return []comparator{func(semver) bool { return false }}, true
+		}
+		return []comparator{func(semver) bool { return true }}, true
+	}
+	switch op {
+	case "", "=":
+		if n == 3 {
+			return []comparator{func(v semver) bool { return compareSemver(v, lower) == 0 }}, true
+		}
+		return []comparator{ge(lower), lt(next())}, true
+	case ">=":
+		return []comparator{ge(lower)}, true
+	case ">":
+		if n == 3 {
+			return []comparator{gt(lower)}, true
+		}
+		return []comparator{ge(next())}, true
+	case "<":
+		return []comparator{lt(lower)}, true
+	case "<=":
+		if n == 3 {
+			return []comparator{le(lower)}, true
+		}
+		return []comparator{lt(next())}, true
+	case "~", "~>":
+		if n == 1 {
+			return []comparator{ge(lower), lt(at(p[0]+1, 0, 0, "0"))}, true
+		}
+		return []comparator{ge(lower), lt(at(p[0], p[1]+1, 0, "0"))}, true
+	case "^":
+		switch {
+		case p[0] > 0 || n == 1:
+			return []comparator{ge(lower), lt(at(p[0]+1, 0, 0, "0"))}, true
+		case p[1] > 0 || n == 2:
+			return []comparator{ge(lower), lt(at(0, p[1]+1, 0, "0"))}, true
+		default:
+			return []comparator{ge(lower), lt(at(0, 0, p[2]+1, "0"))}, true
+		}
+	}
+	return nil, false
+}
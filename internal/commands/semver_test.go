@@ -0,0 +1,88 @@
+package commands
+
+import "testing"
+
+func TestSatisfies(t *testing.T) {
+	tests := []struct {
+		version string
+		spec    string
+		want    bool
+	}{
+		{"1.2.3", "1.2.3", true},
+		{"1.2.4", "1.2.3", false},
+		{"1.9.0", "^1.2.3", true},
+		{"2.0.0", "^1.2.3", false},
+		{"0.2.9", "^0.2.3", true},
+		{"0.3.0", "^0.2.3", false},
+		{"0.0.3", "^0.0.3", true},
+		{"0.0.4", "^0.0.3", false},
+		{"1.2.9", "~1.2.3", true},
+		{"1.3.0", "~1.2.3", false},
+		{"1.9.9", "~1", true},
+		{"1.4.0", "1.x", true},
+		{"2.0.0", "1.x", false},
+		{"1.2.7", "1.2", true},
+		{"3.0.0", "*", true},
+		{"3.0.0", "", true},
+		{"1.5.0", ">=1.2 <2", true},
+		{"2.0.0", ">=1.2 <2", false},
+		{"1.2.0", ">= 1.2", true},
+		{"1.0.0", ">1", false},
+		{"2.0.0", ">1", true},
+		{"1.9.9", "<=1", true},
+		{"2.0.0", "<=1", false},
+		{"3.1.0", "^1.0.0 || ^3.0.0", true},
+		{"2.1.0", "^1.0.0 || ^3.0.0", false},
+
+		// hyphen ranges: inclusive bounds, partial upper bound rounds up
+		{"1.2.0", "1.2 - 2.3", true},
+		{"2.3.9", "1.2 - 2.3", true},
+		{"2.4.0", "1.2 - 2.3", false},
+		{"1.1.9", "1.2 - 2.3", false},
+
+		// prereleases only match a comparator with a prerelease on the same
+		// MAJOR.MINOR.PATCH
+		{"2.0.0-beta.1", "^1.0.0", false},
+		{"1.5.0-rc.1", "1.2.0 - 2.0.0", false},
+		{"1.5.0-rc.1", ">=1.0.0", false},
+		{"1.2.3-beta.2", ">=1.2.3-beta.1", true},
+		{"1.2.3-alpha", ">=1.2.3-beta.1", false},
+		{"1.2.4-beta.1", ">=1.2.3-beta.1", false},
+		{"1.2.4", ">=1.2.3-beta.1", true},
+		{"1.2.3-rc.1", "^1.2.3-beta.1", true},
+		{"1.3.0-rc.1", "^1.2.3-beta.1", false},
+		{"2.0.0-rc.1", "1.0.0 - 2.0.0-rc.2", true},
+		{"1.5.0-rc.1", "1.0.0 - 2.0.0-rc.2", false},
+		{"1.0.0-rc.1", "^0.9.0 || >=1.0.0-rc.0", true},
+	}
+	for _, tt := range tests {
+		v, ok := parseSemver(tt.version)
+		if !ok {
+			t.Fatalf("parseSemver(%q) failed", tt.version)
+		}
+		if got := satisfies(v, tt.spec); got != tt.want {
+			t.Errorf("satisfies(%q, %q) = %v, want %v", tt.version, tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestMaxSatisfying(t *testing.T) {
+	versions := []string{"1.0.0", "1.2.0", "1.3.0-beta.1", "1.2.5", "2.0.0-rc.1", "2.0.0", "2.1.0"}
+	tests := []struct {
+		spec string
+		want string
+		ok   bool
+	}{
+		{"^1.0.0", "1.2.5", true},
+		{"~1.2.0", "1.2.5", true},
+		{">=1.3.0-beta.0 <2", "1.3.0-beta.1", true},
+		{"^2.0.0-rc.0", "2.1.0", true},
+		{"^3", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := maxSatisfying(versions, tt.spec)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("maxSatisfying(%q) = %q, %v, want %q, %v", tt.spec, got, ok, tt.want, tt.ok)
+		}
+	}
+}
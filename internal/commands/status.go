@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"net/http"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// RegistryURL is the npm registry the app talks to.
+const RegistryURL = "https://registry.npmjs.com"
+
+// ToastLevel grades a toast notification.
+type ToastLevel int
+
+const (
+	ToastSuccess ToastLevel = iota
+	ToastWarning
+	ToastError
+)
+
+// ToastMsg asks the app to show a transient notification in the status bar.
+// Any command can return one.
+type ToastMsg struct {
+	Level ToastLevel
+	Text  string
+}
+
+// Toast returns a command that emits a ToastMsg.
+func Toast(level ToastLevel, text string) tea.Cmd {
+	return func() tea.Msg { return ToastMsg{Level: level, Text: text} }
+}
+
+// ConnectivityMsg reports whether the registry answered.
+type ConnectivityMsg struct {
+	Online bool
+	// Req is the request sequence passed to CheckOnline
+	Req int
+}
+
+// CheckOnline pings the registry, after delay when it is positive.
+func CheckOnline(delay time.Duration, req int) tea.Cmd {
+	ping := func() tea.Msg {
+		client := &http.Client{Timeout: 5 * time.Second}
+		resp, err := client.Head(RegistryURL + "/-/ping")
+		if err != nil {
+			return ConnectivityMsg{Req: req}
+		}
+		resp.Body.Close()
+		return ConnectivityMsg{Online: true, Req: req}
+	}
+	if delay <= 0 {
+		return ping
+	}
+	return tea.Tick(delay, func(time.Time) tea.Msg { return ping() })
+}
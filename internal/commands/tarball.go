@@ -0,0 +1,282 @@
+package commands
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxTarballBytes bounds how much of a tarball we are willing to download.
+const maxTarballBytes = 64 << 20
+
+// errFileNotInTarball is returned when no tarball entry matches.
+var errFileNotInTarball = errors.New("file not found in tarball")
+
+// tarballEntryPath strips the leading "package/" directory npm puts in front of
+// every file in a published tarball.
+func tarballEntryPath(name string) string {
+	name = strings.TrimPrefix(name, "./")
+	if i := strings.IndexByte(name, '/'); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+// TarballFile is a regular file in a published package tarball.
+type TarballFile struct {
+	Path string // relative to the package root
+	Size int64
+}
+
+// TarballMsg lists the files of a package tarball downloaded to the cache.
+type TarballMsg struct {
+	Package string
+	Version string
+	Archive string // path of the cached .tgz
+	Files   []TarballFile
+	Err     error
+}
+
+// TarballFileMsg carries the content of one file read from a cached tarball.
+type TarballFileMsg struct {
+	Archive string
+	Path    string
+	Content []byte
+	Err     error
+}
+
+// maxTarballFileBytes bounds how much of a single file we load for viewing.
+const maxTarballFileBytes = 1 << 20
+
+// tarballCacheDir is where verified tarballs are kept between sessions.
+func tarballCacheDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "npm-tui", "tarballs"), nil
+}
+
+// FetchTarball downloads the latest tarball of pkg into the cache (reusing a
+// cached copy), verifies it against dist.integrity/dist.shasum and lists its
+// files.
+func FetchTarball(pkg string) tea.Cmd {
+	return func() tea.Msg {
+		client := &http.Client{Timeout: 30 * time.Second}
+		p, err := fetchPackument(client, pkg)
+		if err != nil {
+			return TarballMsg{Package: pkg, Err: err}
+		}
+		v, ok := p.latestVersion()
+		if !ok {
+			return TarballMsg{Package: pkg, Err: errNoLatest}
+		}
+		if v.Dist.Tarball == "" {
+			return TarballMsg{Package: pkg, Version: v.Version, Err: errors.New("no tarball published for " + pkg)}
+		}
+		archive, err := cachedTarball(client, pkg, v)
+		if err != nil {
+			return TarballMsg{Package: pkg, Version: v.Version, Err: err}
+		}
+		files, err := listTarball(archive)
+		if err != nil {
+			return TarballMsg{Package: pkg, Version: v.Version, Archive: archive, Err: err}
+		}
+		return TarballMsg{Package: pkg, Version: v.Version, Archive: archive, Files: files}
+	}
+}
+
+// cachedTarball returns the path of a verified copy of v's tarball, downloading
+// it when missing or when the cached copy fails verification.
+func cachedTarball(client *http.Client, pkg string, v packumentVersion) (string, error) {
+	dir, err := tarballCacheDir()
+	if err != nil {
+		return "", err
+	}
+	// Scoped names contain a slash; keep one flat directory of archives
+	name := strings.ReplaceAll(strings.TrimPrefix(pkg, "@"), "/", "+") + "-" + v.Version + ".tgz"
+	archive := filepath.Join(dir, name)
+	if b, err := os.ReadFile(archive); err == nil && verifyIntegrity(b, v.Dist.Integrity, v.Dist.Shasum) == nil {
+		return archive, nil
+	}
+	resp, err := client.Get(v.Dist.Tarball)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("tarball download returned %s", resp.Status)
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxTarballBytes+1))
+	if err != nil {
+		return "", err
+	}
+	if len(b) > maxTarballBytes {
+		return "", fmt.Errorf("tarball is larger than %d MB", maxTarballBytes>>20)
+	}
+	if err := verifyIntegrity(b, v.Dist.Integrity, v.Dist.Shasum); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	// Write atomically so a concurrent reader never sees a partial archive
+	tmp, err := os.CreateTemp(dir, name+".*")
+	if err != nil {
+		return "", err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), archive); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return archive, nil
+}
+
+// verifyIntegrity checks data against an SRI integrity string (e.g.
+// "sha512-<base64>"; any listed hash may match) or, for old packages that only
+// publish it, the hex sha1 shasum. Missing checksums are an error.
+func verifyIntegrity(data []byte, integrity, shasum string) error {
+	if integrity = strings.TrimSpace(integrity); integrity != "" {
+		supported := false
+		for _, entry := range strings.Fields(integrity) {
+			algo, digest, ok := strings.Cut(entry, "-")
+			if !ok {
+				continue
+			}
+			// drop SRI options ("sha512-abc?opt")
+			digest, _, _ = strings.Cut(digest, "?")
+			var sum []byte
+			switch algo {
+			case "sha512":
+				s := sha512.Sum512(data)
+				sum = s[:]
+			case "sha384":
+				s := sha512.Sum384(data)
+				sum = s[:]
+			case "sha256":
+				s := sha256.Sum256(data)
+				sum = s[:]
+			case "sha1":
+				s := sha1.Sum(data)
+				sum = s[:]
+			default:
+				continue
+			}
+			supported = true
+			if base64.StdEncoding.EncodeToString(sum) == digest {
+				return nil
+			}
+		}
+		if supported {
+			return errors.New("tarball integrity check failed")
+		}
+	}
+	if shasum = strings.TrimSpace(shasum); shasum != "" {
+		s := sha1.Sum(data)
+		if strings.EqualFold(hex.EncodeToString(s[:]), shasum) {
+			return nil
+		}
+		return errors.New("tarball shasum check failed")
+	}
+	return errors.New("tarball has no integrity checksum")
+}
+
+// openTarball opens a cached .tgz for reading its entries.
+func openTarball(archive string) (*tar.Reader, func(), error) {
+	f, err := os.Open(archive)
+	if err != nil {
+		return nil, nil, err
+	}
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	return tar.NewReader(gz), func() { gz.Close(); f.Close() }, nil
+}
+
+// listTarball returns the regular files in a cached tarball sorted by path.
+func listTarball(archive string) ([]TarballFile, error) {
+	tr, closeFn, err := openTarball(archive)
+	if err != nil {
+		return nil, err
+	}
+	defer closeFn()
+	var files []TarballFile
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		files = append(files, TarballFile{Path: tarballEntryPath(hdr.Name), Size: hdr.Size})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+// ReadTarballEntry loads one file (up to maxTarballFileBytes) from a cached tarball.
+func ReadTarballEntry(archive, path string) tea.Cmd {
+	return func() tea.Msg {
+		_, b, err := readArchiveFile(archive, func(p string) bool { return p == path })
+		return TarballFileMsg{Archive: archive, Path: path, Content: b, Err: err}
+	}
+}
+
+// readArchiveFile returns the first regular file in a cached tarball whose
+// path (relative to the package root) satisfies match, reading at most
+// maxTarballFileBytes of it.
+func readArchiveFile(archive string, match func(path string) bool) (string, []byte, error) {
+	tr, closeFn, err := openTarball(archive)
+	if err != nil {
+		return "", nil, err
+	}
+	defer closeFn()
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return "", nil, errFileNotInTarball
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		p := tarballEntryPath(hdr.Name)
+		if !match(p) {
+			continue
+		}
+		b, err := io.ReadAll(io.LimitReader(tr, maxTarballFileBytes))
+		return p, b, err
+	}
+}
@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestVerifyIntegrity(t *testing.T) {
+	data := []byte("package contents")
+	sri := func(algo string, sum []byte) string { return algo + "-" + base64.StdEncoding.EncodeToString(sum) }
+	s512 := sha512.Sum512(data)
+	s384 := sha512.Sum384(data)
+	s256 := sha256.Sum256(data)
+	s1 := sha1.Sum(data)
+	other := sha512.Sum512([]byte("tampered"))
+	shasum := hex.EncodeToString(s1[:])
+
+	tests := []struct {
+		name      string
+		integrity string
+		shasum    string
+		wantErr   bool
+	}{
+		{"sha512", sri("sha512", s512[:]), "", false},
+		{"sha384", sri("sha384", s384[:]), "", false},
+		{"sha256", sri("sha256", s256[:]), "", false},
+		{"sha1 sri", sri("sha1", s1[:]), "", false},
+		{"options are ignored", sri("sha512", s512[:]) + "?foo", "", false},
+		{"any listed hash may match", sri("sha512", other[:]) + " " + sri("sha256", s256[:]), "", false},
+		{"unsupported algorithm skipped", "md5-abc " + sri("sha512", s512[:]), "", false},
+		{"mismatch", sri("sha512", other[:]), "", true},
+		{"mismatch does not fall back to shasum", sri("sha512", other[:]), shasum, true},
+		{"only unsupported algorithms fall back to shasum", "md5-abc", shasum, false},
+		{"shasum", "", shasum, false},
+		{"shasum is case-insensitive", "", "  " + strings.ToUpper(shasum) + " ", false},
+		{"shasum mismatch", "", hex.EncodeToString(other[:20]), true},
+		{"no checksum", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyIntegrity(data, tt.integrity, tt.shasum)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("verifyIntegrity() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// toolchain.go: which package manager version a project uses, and the
+// install commands that version understands.
+
+// Toolchain is a package manager and its version. Version is empty when it
+// could not be determined.
+type Toolchain struct {
+	PM      PackageManager
+	Version string
+	// Corepack runs the package manager through corepack, which fetches the
+	// version declared in package.json
+	Corepack bool
+}
+
+// String renders the toolchain as "pnpm 9.1.0", or just the name when the
+// version is unknown.
+func (t Toolchain) String() string {
+	s := string(t.PM)
+	if t.Version != "" {
+		s += " " + t.Version
+	}
+	if t.Corepack {
+		s += " via corepack"
+	}
+	return s
+}
+
+// major returns the major version, or -1 when unknown.
+func (t Toolchain) major() int {
+	parts, n, _, ok := parsePartial(t.Version)
+	if !ok || n == 0 {
+		return -1
+	}
+	return parts[0]
+}
+
+// installBuilder builds the command that installs pkg, or updates it to the
+// latest version when installed.
+type installBuilder func(pkg string, dev, installed bool) (string, []string)
+
+// installBuilder returns the command builder for the toolchain. Unknown
+// versions get the long-standing syntax of each package manager.
+func (t Toolchain) installBuilder() installBuilder {
+	switch t.PM {
+	case PMPNPM:
+		return pnpmInstall
+	case PMYarn:
+		// Yarn 2+ (Berry) replaced `upgrade --latest` with `up`
+		if t.major() >= 2 {
+			return yarnBerryInstall
+		}
+		return yarnClassicInstall
+	case PMBun:
+		return bunInstall
+	}
+	return npmInstall
+}
+
+func npmInstall(pkg string, dev, installed bool) (string, []string) {
+	// install <pkg>@latest both installs and updates; the dev flag only
+	// applies to fresh installs so updates keep the dependency's section
+	args := []string{"install"}
+	if dev && !installed {
+		args = append(args, "--save-dev")
+	}
+	return "npm", append(args, pkg+"@latest")
+}
+
+func pnpmInstall(pkg string, dev, installed bool) (string, []string) {
+	if installed {
+		// Without --latest, pnpm up stays within the manifest's range
+		return "pnpm", []string{"up", "--latest", pkg}
+	}
+	args := []string{"add"}
+	if dev {
+		args = append(args, "--save-dev")
+	}
+	return "pnpm", append(args, pkg)
+}
+
+func yarnClassicInstall(pkg string, dev, installed bool) (string, []string) {
+	if installed {
+		return "yarn", []string{"upgrade", "--latest", pkg}
+	}
+	args := []string{"add"}
+	if dev {
+		args = append(args, "-D")
+	}
+	return "yarn", append(args, pkg)
+}
+
+func yarnBerryInstall(pkg string, dev, installed bool) (string, []string) {
+	if installed {
+		// yarn up moves to the latest version regardless of the range
+		return "yarn", []string{"up", pkg}
+	}
+	args := []string{"add"}
+	if dev {
+		args = append(args, "-D")
+	}
+	return "yarn", append(args, pkg)
+}
+
+func bunInstall(pkg string, dev, installed bool) (string, []string) {
+	if installed {
+		// bun upgrade updates bun itself; re-adding at latest keeps the
+		// dependency's section
+		return "bun", []string{"add", pkg + "@latest"}
+	}
+	args := []string{"add"}
+	if dev {
+		args = append(args, "-d")
+	}
+	return "bun", append(args, pkg)
+}
+
+// installArgs builds the command that installs pkg, or updates it when
+// installed, prefixed with corepack when the toolchain runs through it.
+func (t Toolchain) installArgs(pkg string, dev, installed bool) (string, []string) {
+	name, args := t.installBuilder()(pkg, dev, installed)
+	if t.Corepack {
+		return "corepack", append([]string{name}, args...)
+	}
+	return name, args
+}
+
+// resolveToolchain detects the package manager of the project at cwd and
+// the version whose commands to use.
+func resolveToolchain(cwd string, preferred PackageManager, corepack bool) Toolchain {
+	return projectInfo(cwd, preferred).Toolchain(corepack)
+}
+
+// declaredPackageManager reads the packageManager field (e.g.
+// "pnpm@9.1.0+sha512.…") of the nearest package.json.
+func declaredPackageManager(cwd string) (PackageManager, string) {
+	p := findPackageJSON(cwd)
+	if p == "" {
+		return "", ""
+	}
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return "", ""
+	}
+	var data struct {
+		PackageManager string `json:"packageManager"`
+	}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return "", ""
+	}
+	name, version, _ := strings.Cut(data.PackageManager, "@")
+	// Drop the integrity hash corepack accepts after the version
+	version, _, _ = strings.Cut(version, "+")
+	switch pm := PackageManager(name); pm {
+	case PMNPM, PMPNPM, PMYarn, PMBun:
+		return pm, version
+	}
+	return "", ""
+}
+
+// binaryVersions caches `<pm> --version` by package manager and directory;
+// yarn and corepack shims answer per project.
+var binaryVersions = struct {
+	sync.Mutex
+	m map[string]string
+}{m: map[string]string{}}
+
+// binaryVersion runs `<pm> --version` in cwd. It returns "" when the binary
+// is missing or fails.
+func binaryVersion(cwd string, pm PackageManager) string {
+	key := string(pm) + "\x00" + cwd
+	binaryVersions.Lock()
+	v, ok := binaryVersions.m[key]
+	binaryVersions.Unlock()
+	if ok {
+		return v
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, string(pm), "--version")
+	cmd.Dir = cwd
+	if out, err := cmd.Output(); err == nil {
+		v = strings.TrimPrefix(strings.TrimSpace(string(out)), "v")
+	}
+	binaryVersions.Lock()
+	binaryVersions.m[key] = v
+	binaryVersions.Unlock()
+	return v
+}
@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestProjectToolchain(t *testing.T) {
+	tests := []struct {
+		name     string
+		project  ProjectInfoMsg
+		corepack bool
+		want     Toolchain
+	}{
+		{
+			name:    "declared yarn 4 with yarn 1 on PATH uses the classic commands",
+			project: ProjectInfoMsg{PackageManager: PMYarn, Declared: "4.1.0", Installed: "1.22.19"},
+			want:    Toolchain{PM: PMYarn, Version: "1.22.19"},
+		},
+		{
+			name:     "corepack runs the declared version",
+			project:  ProjectInfoMsg{PackageManager: PMYarn, Declared: "4.1.0", Installed: "1.22.19"},
+			corepack: true,
+			want:     Toolchain{PM: PMYarn, Version: "4.1.0", Corepack: true},
+		},
+		{
+			name:     "corepack without a declared version keeps the installed one",
+			project:  ProjectInfoMsg{PackageManager: PMPNPM, Installed: "9.1.0"},
+			corepack: true,
+			want:     Toolchain{PM: PMPNPM, Version: "9.1.0", Corepack: true},
+		},
+		{
+			name:    "missing binary leaves the version unknown",
+			project: ProjectInfoMsg{PackageManager: PMPNPM, Declared: "9.1.0"},
+			want:    Toolchain{PM: PMPNPM},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.project.Toolchain(tt.corepack); got != tt.want {
+				t.Errorf("Toolchain(%v) = %+v, want %+v", tt.corepack, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInstallArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		tc        Toolchain
+		dev       bool
+		installed bool
+		want      []string
+	}{
+		{"npm add", Toolchain{PM: PMNPM, Version: "10.8.1"}, false, false, []string{"npm", "install", "react@latest"}},
+		{"npm add dev", Toolchain{PM: PMNPM, Version: "10.8.1"}, true, false, []string{"npm", "install", "--save-dev", "react@latest"}},
+		{"npm update keeps section", Toolchain{PM: PMNPM, Version: "10.8.1"}, true, true, []string{"npm", "install", "react@latest"}},
+		{"npm corepack", Toolchain{PM: PMNPM, Version: "10.8.1", Corepack: true}, false, false, []string{"corepack", "npm", "install", "react@latest"}},
+
+		{"pnpm add", Toolchain{PM: PMPNPM, Version: "9.1.0"}, false, false, []string{"pnpm", "add", "react"}},
+		{"pnpm add dev", Toolchain{PM: PMPNPM, Version: "9.1.0"}, true, false, []string{"pnpm", "add", "--save-dev", "react"}},
+		{"pnpm update", Toolchain{PM: PMPNPM, Version: "9.1.0"}, false, true, []string{"pnpm", "up", "--latest", "react"}},
+		{"pnpm update corepack", Toolchain{PM: PMPNPM, Version: "9.1.0", Corepack: true}, true, true, []string{"corepack", "pnpm", "up", "--latest", "react"}},
+
+		{"yarn 1 add", Toolchain{PM: PMYarn, Version: "1.22.19"}, false, false, []string{"yarn", "add", "react"}},
+		{"yarn 1 add dev", Toolchain{PM: PMYarn, Version: "1.22.19"}, true, false, []string{"yarn", "add", "-D", "react"}},
+		{"yarn 1 update", Toolchain{PM: PMYarn, Version: "1.22.19"}, false, true, []string{"yarn", "upgrade", "--latest", "react"}},
+		{"yarn unknown version is classic", Toolchain{PM: PMYarn}, false, true, []string{"yarn", "upgrade", "--latest", "react"}},
+		{"yarn 4 add dev", Toolchain{PM: PMYarn, Version: "4.1.0"}, true, false, []string{"yarn", "add", "-D", "react"}},
+		{"yarn 4 update", Toolchain{PM: PMYarn, Version: "4.1.0"}, false, true, []string{"yarn", "up", "react"}},
+		{"yarn 2 update", Toolchain{PM: PMYarn, Version: "2.4.3"}, false, true, []string{"yarn", "up", "react"}},
+		{"yarn 4 update corepack", Toolchain{PM: PMYarn, Version: "4.1.0", Corepack: true}, false, true, []string{"corepack", "yarn", "up", "react"}},
+
+		{"bun add", Toolchain{PM: PMBun, Version: "1.1.8"}, false, false, []string{"bun", "add", "react"}},
+		{"bun add dev", Toolchain{PM: PMBun, Version: "1.1.8"}, true, false, []string{"bun", "add", "-d", "react"}},
+		{"bun update", Toolchain{PM: PMBun, Version: "1.1.8"}, true, true, []string{"bun", "add", "react@latest"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, args := tt.tc.installArgs("react", tt.dev, tt.installed)
+			if got := append([]string{name}, args...); !slices.Equal(got, tt.want) {
+				t.Errorf("installArgs(dev=%v, installed=%v) = %q, want %q", tt.dev, tt.installed, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package commands
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TrendDirection classifies how a package's weekly downloads are moving.
+type TrendDirection int
+
+const (
+	TrendUnknown TrendDirection = iota
+	TrendRising
+	TrendStable
+	TrendDeclining
+)
+
+func (t TrendDirection) String() string {
+	switch t {
+	case TrendRising:
+		return "rising"
+	case TrendStable:
+		return "stable"
+	case TrendDeclining:
+		return "declining"
+	default:
+		return ""
+	}
+}
+
+// trendThreshold is the relative change (recent vs. baseline) beyond which a
+// package counts as rising or declining.
+const trendThreshold = 0.10
+
+// DownloadTrend summarizes a package's weekly download series.
+type DownloadTrend struct {
+	// WeekOverWeek is the percent change of the last full week vs. the one before.
+	WeekOverWeek    float64
+	HasWeekOverWeek bool
+	// YearOverYear is the percent change of the last full week vs. the same week a year earlier.
+	YearOverYear    float64
+	HasYearOverYear bool
+	PeakWeek        time.Time
+	PeakValue       float64
+	// MovingAverage is the mean of the last four weeks.
+	MovingAverage float64
+	Direction     TrendDirection
+	// Window is the span of daily downloads the trend was computed from
+	Window DownloadsWindow
+}
+
+// DownloadTrendsMsg carries trend summaries keyed by package name.
+type DownloadTrendsMsg struct {
+	Trends map[string]DownloadTrend
+}
+
+// TrendRowWindow covers 54 weeks: enough for year-over-year, and short
+// enough to fetch in a single downloads range request per package.
+const TrendRowWindow DownloadsWindow = 7 * 54
+
+// FetchDownloadTrends computes weekly download trends over window for each
+// name. Packages whose downloads cannot be fetched are omitted.
+func FetchDownloadTrends(names []string, window DownloadsWindow) tea.Cmd {
+	return func() tea.Msg {
+		client := &http.Client{Timeout: 8 * time.Second}
+		type out struct {
+			name  string
+			trend DownloadTrend
+			ok    bool
+		}
+		sem := make(chan struct{}, 4)
+		done := make(chan out, len(names))
+		for _, nm := range names {
+			nm := nm
+			sem <- struct{}{}
+			go func() {
+				defer func() { <-sem }()
+				days, err := fetchDailyDownloads(client, nm, window)
+				if err != nil {
+					done <- out{name: nm}
+					return
+				}
+				t := computeTrend(aggregateDownloads(days, GranularityWeekly))
+				t.Window = window
+				done <- out{name: nm, trend: t, ok: true}
+			}()
+		}
+		trends := make(map[string]DownloadTrend, len(names))
+		for range names {
+			o := <-done
+			if o.ok {
+				trends[o.name] = o.trend
+			}
+		}
+		return DownloadTrendsMsg{Trends: trends}
+	}
+}
+
+// computeTrend derives growth figures and a direction from weekly points
+// ordered oldest..newest.
+func computeTrend(pts []DownloadPoint) DownloadTrend {
+	var t DownloadTrend
+	n := len(pts)
+	if n == 0 {
+		return t
+	}
+	for _, p := range pts {
+		if p.Value > t.PeakValue {
+			t.PeakValue = p.Value
+			t.PeakWeek = p.Time
+		}
+	}
+	last := pts[n-1].Value
+	if n >= 2 && pts[n-2].Value > 0 {
+		t.WeekOverWeek = (last - pts[n-2].Value) / pts[n-2].Value * 100
+		t.HasWeekOverWeek = true
+	}
+	// Look the year-ago week up by date; the series can have gaps
+	if ago, ok := pointNear(pts, pts[n-1].Time.AddDate(0, 0, -7*52)); ok && ago.Value > 0 {
+		t.YearOverYear = (last - ago.Value) / ago.Value * 100
+		t.HasYearOverYear = true
+	}
+	t.MovingAverage = meanValue(pts[max(0, n-4):])
+	// Compare the last four weeks with up to twelve weeks before them
+	if n >= 8 {
+		baseline := meanValue(pts[max(0, n-16) : n-4])
+		switch {
+		case baseline <= 0:
+			if t.MovingAverage > 0 {
+				t.Direction = TrendRising
+			} else {
+				t.Direction = TrendStable
+			}
+		case (t.MovingAverage-baseline)/baseline > trendThreshold:
+			t.Direction = TrendRising
+		case (t.MovingAverage-baseline)/baseline < -trendThreshold:
+			t.Direction = TrendDeclining
+		default:
+			t.Direction = TrendStable
+		}
+	}
+	return t
+}
+
+// pointNear returns the point of pts (ordered oldest..newest) closest to at,
+// provided it lies within half a week of it.
+func pointNear(pts []DownloadPoint, at time.Time) (DownloadPoint, bool) {
+	const tolerance = 84 * time.Hour
+	i := sort.Search(len(pts), func(i int) bool { return !pts[i].Time.Before(at) })
+	best, found := DownloadPoint{}, false
+	bestDist := tolerance + 1
+	for _, j := range []int{i - 1, i} {
+		if j < 0 || j >= len(pts) {
+			continue
+		}
+		if d := pts[j].Time.Sub(at).Abs(); d <= tolerance && d < bestDist {
+			best, bestDist, found = pts[j], d, true
+		}
+	}
+	return best, found
+}
+
+func meanValue(pts []DownloadPoint) float64 {
+	if len(pts) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, p := range pts {
+		sum += p.Value
+	}
+	return sum / float64(len(pts))
+}
@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// weeklySeries returns weekly points starting at start, skipping the weeks
+// in missing.
+func weeklySeries(start time.Time, values []float64, missing map[int]bool) []DownloadPoint {
+	var pts []DownloadPoint
+	for i, v := range values {
+		if missing[i] {
+			continue
+		}
+		pts = append(pts, DownloadPoint{Time: start.AddDate(0, 0, 7*i), Value: v})
+	}
+	return pts
+}
+
+func TestComputeTrendYearOverYear(t *testing.T) {
+	start := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+	values := make([]float64, 60)
+	for i := range values {
+		values[i] = float64(100 + i)
+	}
+	// the week a year before the last one is index 59-52 = 7
+	want := (values[59] - values[7]) / values[7] * 100
+
+	tests := []struct {
+		name    string
+		missing map[int]bool
+		ok      bool
+	}{
+		{"complete series", nil, true},
+		{"gap inside the year", map[int]bool{20: true, 30: true}, true},
+		{"gap before the year-ago week", map[int]bool{2: true}, true},
+		{"year-ago week missing", map[int]bool{7: true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := computeTrend(weeklySeries(start, values, tt.missing))
+			if tr.HasYearOverYear != tt.ok {
+				t.Fatalf("HasYearOverYear = %v, want %v", tr.HasYearOverYear, tt.ok)
+			}
+			if tt.ok && math.Abs(tr.YearOverYear-want) > 1e-9 {
+				t.Errorf("YearOverYear = %.3f, want %.3f", tr.YearOverYear, want)
+			}
+		})
+	}
+}
+
+func TestComputeTrendShortSeries(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tr := computeTrend(weeklySeries(start, []float64{10, 20}, nil))
+	if tr.HasYearOverYear {
+		t.Error("HasYearOverYear set for a two-week series")
+	}
+	if !tr.HasWeekOverWeek || tr.WeekOverWeek != 100 {
+		t.Errorf("WeekOverWeek = %v (%v), want 100", tr.WeekOverWeek, tr.HasWeekOverWeek)
+	}
+}
+
+func TestTrendRowWindow(t *testing.T) {
+	end := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	start := end.AddDate(0, 0, -(int(TrendRowWindow) - 1))
+	if chunkEnd := start.AddDate(0, maxRangeMonths, -1); chunkEnd.Before(end) {
+		t.Errorf("row window needs more than one range request (first chunk ends %s)", chunkEnd.Format("2006-01-02"))
+	}
+	var days []dailyDownloads
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		days = append(days, dailyDownloads{Day: d, Downloads: 10})
+	}
+	if tr := computeTrend(aggregateDownloads(days, GranularityWeekly)); !tr.HasYearOverYear {
+		t.Errorf("row window too short for year-over-year")
+	}
+}
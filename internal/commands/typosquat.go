@@ -0,0 +1,187 @@
+package commands
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// typosquatRatio is how many times more weekly downloads a similarly named
+// package needs before we assume the user meant that one instead.
+const typosquatRatio = 100
+
+// popularPackages are among the most downloaded packages on npm and the
+// usual targets of typosquatting.
+var popularPackages = []string{
+	"react", "react-dom", "vue", "angular", "svelte", "next", "nuxt", "preact", "jquery", "lodash",
+	"lodash-es", "underscore", "ramda", "moment", "dayjs", "date-fns", "luxon", "axios", "node-fetch",
+	"cross-fetch", "got", "request", "superagent", "express", "koa", "fastify", "hapi", "body-parser",
+	"cors", "helmet", "morgan", "cookie-parser", "express-session", "passport", "jsonwebtoken",
+	"bcrypt", "bcryptjs", "uuid", "nanoid", "chalk", "colors", "kleur", "picocolors", "commander",
+	"yargs", "minimist", "inquirer", "prompts", "ora", "debug", "dotenv", "cross-env", "rimraf",
+	"mkdirp", "glob", "fast-glob", "minimatch", "micromatch", "chokidar", "fs-extra", "graceful-fs",
+	"semver", "typescript", "ts-node", "tslib", "@types/node", "@types/react", "webpack", "webpack-cli",
+	"rollup", "vite", "esbuild", "parcel", "babel-loader", "@babel/core", "@babel/preset-env",
+	"core-js", "regenerator-runtime", "eslint", "prettier", "jest", "mocha", "chai", "sinon",
+	"vitest", "cypress", "playwright", "puppeteer", "karma", "ava", "nodemon", "pm2", "concurrently",
+	"husky", "lint-staged", "mongoose", "mongodb", "mysql", "mysql2", "pg", "sequelize", "typeorm",
+	"prisma", "knex", "redis", "ioredis", "socket.io", "socket.io-client", "ws", "graphql",
+	"apollo-server", "@apollo/client", "redux", "react-redux", "@reduxjs/toolkit", "mobx", "zustand",
+	"rxjs", "immer", "immutable", "classnames", "clsx", "styled-components", "@emotion/react",
+	"tailwindcss", "postcss", "autoprefixer", "sass", "less", "bootstrap", "react-router",
+	"react-router-dom", "formik", "yup", "zod", "joi", "ajv", "validator", "qs", "query-string",
+	"cheerio", "jsdom", "marked", "markdown-it", "highlight.js", "handlebars", "ejs", "pug",
+	"mustache", "async", "bluebird", "q", "p-limit", "p-map", "eventemitter3", "events", "buffer",
+	"process", "util", "path-browserify", "crypto-js", "node-forge", "sharp", "jimp", "multer",
+	"form-data", "mime", "mime-types", "http-proxy", "http-proxy-middleware", "ws", "winston",
+	"pino", "bunyan", "electron", "vue-router", "vuex", "pinia", "three", "d3", "chart.js",
+	"echarts", "leaflet", "socket.io", "nodemailer", "twilio", "stripe", "aws-sdk", "firebase",
+	"firebase-admin", "openai", "discord.js", "telegraf", "puppeteer-core", "yaml", "js-yaml",
+	"xml2js", "csv-parse", "papaparse", "xlsx", "pdfkit", "archiver", "tar", "adm-zip", "coffee-script",
+	"object-assign", "inherits", "safe-buffer", "readable-stream", "string_decoder", "once", "ms",
+	"escape-string-regexp", "supports-color", "has-flag", "ansi-styles", "strip-ansi", "ansi-regex",
+	"color-convert", "color-name", "wrap-ansi", "string-width", "yallist", "lru-cache", "signal-exit",
+	"which", "isexe", "cross-spawn", "execa", "shelljs", "tmp", "source-map", "source-map-support",
+	"acorn", "esprima", "estraverse", "terser", "uglify-js", "npm", "yarn", "pnpm",
+}
+
+// TyposquatMsg reports a popular package with a near-identical name.
+type TyposquatMsg struct {
+	Package string
+	// Suggestion is the likely intended package, empty when none was found
+	Suggestion string
+	// Reason names the similarity, e.g. "swapped letters" or "one letter off"
+	Reason              string
+	Downloads           int
+	SuggestionDownloads int
+	Err                 error
+}
+
+// CheckTyposquat compares pkg to popular package names and, when one is
+// nearly identical and far more downloaded, reports it as the likely intended
+// package. Names that are not similar to any popular package need no network.
+func CheckTyposquat(pkg string) tea.Cmd {
+	return func() tea.Msg {
+		cands := similarPopular(pkg)
+		if len(cands) == 0 {
+			return TyposquatMsg{Package: pkg}
+		}
+		client := &http.Client{Timeout: 8 * time.Second}
+		names := []string{pkg}
+		for _, c := range cands {
+			names = append(names, c.name)
+		}
+		downloads := make([]int, len(names))
+		var wg sync.WaitGroup
+		for i, n := range names {
+			wg.Add(1)
+			go func(i int, n string) {
+				defer wg.Done()
+				downloads[i] = fetchWeeklyDownloads(client, n)
+			}(i, n)
+		}
+		wg.Wait()
+		msg := TyposquatMsg{Package: pkg, Downloads: downloads[0]}
+		for i, c := range cands {
+			d := downloads[i+1]
+			if d >= typosquatRatio*max(1, msg.Downloads) && d > msg.SuggestionDownloads {
+				msg.Suggestion, msg.Reason, msg.SuggestionDownloads = c.name, c.reason, d
+			}
+		}
+		return msg
+	}
+}
+
+// similarName is a popular package resembling the checked name.
+type similarName struct {
+	name   string
+	reason string
+}
+
+// similarPopular returns popular packages whose names resemble name through
+// a swapped pair of letters, a single edit, a -js/.js/node- affix or
+// different separators.
+func similarPopular(name string) []similarName {
+	name = strings.ToLower(name)
+	var out []similarName
+	seen := map[string]bool{}
+	for _, p := range popularPackages {
+		if p == name || seen[p] {
+			continue
+		}
+		seen[p] = true
+		if reason := typoReason(name, p); reason != "" {
+			out = append(out, similarName{name: p, reason: reason})
+		}
+	}
+	return out
+}
+
+// typoReason explains how name resembles target, or returns "".
+func typoReason(name, target string) string {
+	if isAdjacentSwap(name, target) {
+		return "swapped letters"
+	}
+	if stripAffixes(name) == stripAffixes(target) {
+		return "same name with a different js/node affix or separators"
+	}
+	// A single edit is only suspicious for names long enough to be unique
+	if len(target) >= 5 && editDistance(name, target) == 1 {
+		return "one letter off"
+	}
+	return ""
+}
+
+// isAdjacentSwap reports whether a and b differ only by two adjacent
+// characters being swapped.
+func isAdjacentSwap(a, b string) bool {
+	if len(a) != len(b) || a == b {
+		return false
+	}
+	i := 0
+	for i < len(a) && a[i] == b[i] {
+		i++
+	}
+	return i+1 < len(a) && a[i] == b[i+1] && a[i+1] == b[i] && a[i+2:] == b[i+2:]
+}
+
+// stripAffixes normalizes a name by dropping common js/node affixes and
+// separators, so "lodash.js", "lodashjs" and "node-lodash" all become "lodash".
+func stripAffixes(s string) string {
+	s = strings.ToLower(s)
+	for _, suf := range []string{".js", "-js", "_js", "js"} {
+		if strings.HasSuffix(s, suf) && len(s) > len(suf)+2 {
+			s = strings.TrimSuffix(s, suf)
+			break
+		}
+	}
+	for _, pre := range []string{"node-", "js-", "node.", "node_"} {
+		s = strings.TrimPrefix(s, pre)
+	}
+	return strings.NewReplacer("-", "", "_", "", ".", "").Replace(s)
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
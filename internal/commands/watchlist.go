@@ -0,0 +1,123 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// watchlistFile is the name of the watchlist file in the config directory.
+const watchlistFile = "watchlist.json"
+
+// Watchlist is the set of packages the user follows across projects, kept in
+// watchlist.json next to the config file.
+type Watchlist struct {
+	Packages []WatchedPackage `json:"packages"`
+}
+
+// WatchedPackage is a followed package and the latest version seen when the
+// watchlist was last shown.
+type WatchedPackage struct {
+	Name string `json:"name"`
+	Seen string `json:"seen,omitempty"`
+}
+
+// Has reports whether name is watched.
+func (w Watchlist) Has(name string) bool {
+	return slices.ContainsFunc(w.Packages, func(p WatchedPackage) bool { return p.Name == name })
+}
+
+// Names returns the watched package names in the order they were starred.
+func (w Watchlist) Names() []string {
+	names := make([]string, 0, len(w.Packages))
+	for _, p := range w.Packages {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// Toggle stars name with latest as its seen version, or unstars it. It
+// reports whether name is watched afterwards.
+func (w *Watchlist) Toggle(name, latest string) bool {
+	if w.Has(name) {
+		w.Packages = slices.DeleteFunc(w.Packages, func(p WatchedPackage) bool { return p.Name == name })
+		return false
+	}
+	w.Packages = append(w.Packages, WatchedPackage{Name: name, Seen: latest})
+	return true
+}
+
+// NewSince returns the version seen last when latest is newer than it.
+func (w Watchlist) NewSince(name, latest string) (string, bool) {
+	for _, p := range w.Packages {
+		if p.Name == name {
+			if p.Seen == "" || latest == "" {
+				return "", false
+			}
+			return p.Seen, compareVersionStrings(latest, p.Seen) > 0
+		}
+	}
+	return "", false
+}
+
+// MarkSeen records latest as the seen version of name.
+func (w *Watchlist) MarkSeen(name, latest string) {
+	for i, p := range w.Packages {
+		if p.Name == name && latest != "" {
+			w.Packages[i].Seen = latest
+		}
+	}
+}
+
+// WatchlistMsg carries the watchlist read at startup.
+type WatchlistMsg struct {
+	Watchlist Watchlist
+	Err       error
+}
+
+// WatchlistSavedMsg reports the result of writing the watchlist.
+type WatchlistSavedMsg struct {
+	Err error
+}
+
+// LoadWatchlist reads the watchlist file. A missing file yields an empty
+// watchlist.
+func LoadWatchlist() tea.Cmd {
+	return func() tea.Msg {
+		var w Watchlist
+		if err := readConfigFile(watchlistFile, &w); err != nil {
+			return WatchlistMsg{Err: err}
+		}
+		return WatchlistMsg{Watchlist: w}
+	}
+}
+
+// watchlistSaver keeps watchlist saves in order.
+var watchlistSaver = &configSaver{name: watchlistFile}
+
+// SaveWatchlist writes w to the watchlist file. Saves run one at a time, and
+// one requested earlier never overwrites a later one.
+func SaveWatchlist(w Watchlist) tea.Cmd {
+	// Copy the packages; the caller keeps changing its watchlist
+	w.Packages = slices.Clone(w.Packages)
+	save := watchlistSaver.save(w)
+	return func() tea.Msg {
+		return WatchlistSavedMsg{Err: save()}
+	}
+}
+
+// LoadWatchedPackages requests metadata for the watched packages and returns
+// them as a NpmSearchMsg with Watchlist set. Installed versions come from the
+// current project, if any.
+func LoadWatchedPackages(names []string) tea.Cmd {
+	return func() tea.Msg {
+		baseDir := ""
+		cwd, _ := os.Getwd()
+		if p := findPackageJSON(cwd); p != "" {
+			baseDir = filepath.Dir(p)
+		}
+		return NpmSearchMsg{Result: NpmSearchResult{Objects: loadPackages(names, baseDir)}, Watchlist: true}
+	}
+}
@@ -0,0 +1,227 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/fredrikmwold/npm-tui/internal/commands"
+	"github.com/fredrikmwold/npm-tui/internal/ui/components"
+)
+
+// Package actions shared by the hotkeys and the command palette. Callers
+// check that the action applies in the current context.
+
+// packageFocus reports whether package actions apply to the focused pane.
+func (m *Model) packageFocus() bool {
+	return m.focus == focusResults || m.focus == focusSide
+}
+
+// toggleDetails opens the sidebar for the selected package, or closes it.
+func (m *Model) toggleDetails() tea.Cmd {
+	if m.sideOpen {
+		m.sideOpen = false
+		m.readmeOpen = false
+		m.readmeLoading = false
+		m.list.SetShowReadmeHotkey(false)
+		// return focus to results when closing
+		m.focus = focusResults
+		m.applyFocus()
+		// Recompute sizes for closed state
+		m.recomputeLayout()
+		return nil
+	}
+	// Open the sidebar with details for the selected item
+	m.sideOpen = true
+	// give keyboard focus to the sidebar when it is opened so its
+	// border shows active color and the list loses the active border
+	m.focus = focusSide
+	m.applyFocus()
+	m.list.SetShowReadmeHotkey(true)
+	if det, ok := m.list.SelectedDetails(); ok {
+		m.showDetails(det)
+	}
+	// Recompute sizes for open state
+	m.recomputeLayout()
+	// Kick off downloads range fetch for the chart
+	m.side.SetChartLabel(m.chartLabel())
+	if name, ok := m.list.SelectedName(); ok {
+		return m.fetchSideData(name)
+	}
+	return nil
+}
+
+// showProject clears the search, returns focus to the input, closes the
+// sidebar and reloads the project packages.
+func (m *Model) showProject() tea.Cmd {
+	m.input.Clear()
+	m.focus = focusInput
+	m.sideOpen = false
+	m.readmeOpen = false
+	m.readmeLoading = false
+	m.applyFocus()
+	// Trigger reload of project packages
+	m.loading = true
+	m.list.SetTitle("Loading project packages…")
+	m.list.SetPlaceholder("Loading project packages…")
+	// Recompute sizes after closing sidebar
+	m.recomputeLayout()
+	return commands.LoadProjectPackages()
+}
+
+// search moves focus to the results and searches npm for q, remembering it
+// in the history. An empty query keeps the current items.
+func (m *Model) search(q string) tea.Cmd {
+	m.focus = focusResults
+	m.applyFocus()
+	q = strings.TrimSpace(q)
+	if q == "" {
+		// Keep current items (e.g., project packages) and do not enter loading state
+		return nil
+	}
+	m.loading = true
+	m.list.SetTitle(m.list.RenderPrefixedTitle("Searching npm", m.spinner.View()))
+	m.list.UsePlainTitleStyle()
+	m.list.SetPlaceholder(fmt.Sprintf("Searching npm %s", m.spinner.View()))
+	return tea.Batch(commands.SearchNPM(q, m.searchSize), m.recordSearch(q))
+}
+
+// toggleTarball opens the tarball browser for the selected package, or
+// closes it.
+func (m *Model) toggleTarball() tea.Cmd {
+	if m.tarballOpen {
+		m.tarballOpen = false
+		m.tarballLoading = false
+		m.recomputeLayout()
+		return nil
+	}
+	name, ok := m.list.SelectedName()
+	if !ok {
+		return nil
+	}
+	m.tarballOpen = true
+	m.tarballLoading = true
+	m.tarballPkg = name
+	m.tarballArchive = ""
+	m.recomputeLayout()
+	m.tarball.SetLoading("Downloading tarball", m.spinner.View())
+	return commands.FetchTarball(name)
+}
+
+// toggleMark marks or unmarks the selected package for comparison.
+func (m *Model) toggleMark() tea.Cmd {
+	name, ok := m.list.SelectedName()
+	if !ok {
+		return nil
+	}
+	if _, full := m.list.ToggleMarked(name, maxCompare); full {
+		return m.toast(components.ToastError, fmt.Sprintf("Compare holds at most %d packages; unmark one first", maxCompare))
+	}
+	return nil
+}
+
+// toggleCompare compares the marked packages, or closes the comparison.
+func (m *Model) toggleCompare() tea.Cmd {
+	if m.compareOpen {
+		m.compareOpen = false
+		m.compareLoading = false
+		m.recomputeLayout()
+		return nil
+	}
+	names := m.list.Marked()
+	if len(names) < 2 {
+		return nil
+	}
+	m.compareOpen = true
+	m.compareLoading = true
+	m.recomputeLayout()
+	m.compare.SetLoading("Loading comparison", m.spinner.View())
+	m.compareReq++
+	return commands.FetchCompare(names, m.compareReq)
+}
+
+// installSelected installs (or updates, when installed) the selected package
+// after the pre-install checks.
+func (m *Model) installSelected(dev bool) tea.Cmd {
+	if name, ok := m.list.SelectedName(); ok {
+		return m.requestInstall(name, dev)
+	}
+	return nil
+}
+
+// cycleChart steps the downloads chart window, or its granularity, and
+// refetches the chart.
+func (m *Model) cycleChart(window bool) tea.Cmd {
+	if window {
+		m.chartWindow = nextWindow(m.chartWindow)
+	} else {
+		m.chartGranularity = nextGranularity(m.chartGranularity)
+	}
+	m.side.SetChartLabel(m.chartLabel())
+	m.side.SetDownloadsValues(nil)
+	m.side.SetDownloadsPoints(nil)
+	if name, ok := m.list.SelectedName(); ok {
+		return m.fetchDownloads(name)
+	}
+	return nil
+}
+
+// toggleChangelog shows the changelog between the installed and the latest
+// version of the selected package, or closes it.
+func (m *Model) toggleChangelog() tea.Cmd {
+	if m.readmeOpen && m.viewerChangelog {
+		m.readmeOpen = false
+		m.readmeLoading = false
+		m.applyFocus()
+		m.recomputeLayout()
+		return nil
+	}
+	det, ok := m.list.SelectedDetails()
+	if !ok {
+		return nil
+	}
+	m.readmeOpen = true
+	m.viewerChangelog = true
+	m.readmeLoading = true
+	m.readmeLabel = "Loading changelog"
+	m.recomputeLayout()
+	m.readme.SetLoading(m.readmeLabel, m.spinner.View())
+	m.readmeReq++
+	return commands.FetchChangelog(det.Name, m.readmeReq)
+}
+
+// toggleReadme shows the README of the selected package, or closes it.
+func (m *Model) toggleReadme() tea.Cmd {
+	if m.readmeOpen && !m.viewerChangelog {
+		m.readmeOpen = false
+		m.readmeLoading = false
+		// When closing README, keep sidebar/results focus cycle intact
+		if m.sideOpen {
+			if m.focus == focusSide {
+				// keep focus on sidebar after README closes
+				m.focus = focusSide
+			} else {
+				m.focus = focusResults
+			}
+		} else {
+			m.focus = focusResults
+		}
+		m.applyFocus()
+		m.recomputeLayout()
+		return nil
+	}
+	det, ok := m.list.SelectedDetails()
+	if !ok {
+		return nil
+	}
+	m.readmeOpen = true
+	m.viewerChangelog = false
+	m.readmeLabel = "Loading readme"
+	m.recomputeLayout()
+	// Show spinner while loading the README
+	m.readmeLoading = true
+	m.readme.SetLoading(m.readmeLabel, m.spinner.View())
+	m.readmeReq++
+	return commands.FetchReadme(det.Name, det.Repository, m.readmeReq)
+}
@@ -1,10 +1,13 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"strings"
+	"path"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -32,8 +35,93 @@ type Model struct {
 	sideOpen bool
 	// whether the fullscreen README viewer is open
 	readmeOpen bool
+	// whether the viewer currently shows the changelog instead of the README
+	viewerChangelog bool
+	// label shown next to the viewer spinner while loading
+	readmeLabel string
+	// repository (owner/repo) and directory of the document in the viewer,
+	// used to resolve relative links
+	readmeRepo string
+	readmeDir  string
 	focus      focusTarget
 
+	// side-by-side comparison of marked packages
+	compare *components.CompareModel
+	// whether the fullscreen comparison view is open
+	compareOpen bool
+	// whether comparison data is currently loading
+	compareLoading bool
+	// sequence of the latest comparison request; older results are dropped
+	compareReq int
+
+	// browser for the files of the selected package's published tarball
+	tarball *components.TarballBrowser
+	// whether the fullscreen tarball browser is open, and for which package
+	tarballOpen    bool
+	tarballLoading bool
+	tarballPkg     string
+	// path of the cached archive being browsed
+	tarballArchive string
+
+	// downloads chart window and bucket size in the sidebar
+	chartWindow      commands.DownloadsWindow
+	chartGranularity commands.Granularity
+	// download trend summaries by package name
+	trends map[string]commands.DownloadTrend
+	// package sizes/install footprints by name, and names still resolving
+	sizes       map[string]commands.PackageSizeMsg
+	sizePending map[string]bool
+	// the footprint walk in flight and how to cancel it; only the selected
+	// package is walked
+	sizeFetching string
+	sizeCancel   context.CancelFunc
+	// supply-chain risk signals by name, and names still loading
+	risks       map[string]commands.RiskMsg
+	riskPending map[string]bool
+	// maintainer and publish details by name, and names still loading
+	publish        map[string]commands.PublishInfoMsg
+	publishPending map[string]bool
+	// typosquat checks by name, and names still being checked
+	squats       map[string]commands.TyposquatMsg
+	squatPending map[string]bool
+	// license policy from the config file, and licenses of listed packages
+	licensePolicy commands.LicensePolicy
+	licenses      map[string]string
+	// configured action keys, search result count and fallback package manager
+	keys           clist.KeyMap
+	searchSize     int
+	packageManager commands.PackageManager
+	// themes in switcher order and the index of the active one
+	themes   []theme.Theme
+	themeIdx int
+	// command palette overlay (Ctrl+P)
+	palette *components.Palette
+	// copy or open menu waiting for its key
+	menu *keyMenu
+	// starred packages, and watched packages with a new version by the
+	// version seen before
+	watchlist commands.Watchlist
+	watchNew  map[string]string
+	// search history and saved queries, and the query waiting for a name
+	history commands.History
+	naming  *string
+	// set once the history and watchlist files were read; until then
+	// nothing is saved, so a pending or failed load cannot be overwritten
+	historyLoaded   bool
+	watchlistLoaded bool
+	// bottom status bar with project details and toasts
+	status *components.StatusBar
+	// detected project, and whether installs run through corepack
+	project  commands.ProjectInfoMsg
+	corepack bool
+	// sequence of the latest registry connectivity check
+	onlineReq int
+	// launch opens URLs in the browser
+	launch commands.Launcher
+	// install waiting for pre-install checks, and install waiting for confirmation
+	awaiting *pendingInstall
+	confirm  *pendingInstall
+
 	// loading spinner for async searches
 	spinner spinner.Model
 	loading bool
@@ -53,23 +141,72 @@ const (
 	focusSide
 )
 
-func New() *Model {
+// maxCompare is the maximum number of packages that can be marked for comparison.
+const maxCompare = 4
+
+// New builds the app from the user configuration. It fails when the
+// configured key bindings or themes are invalid.
+func New(cfg commands.Config) (*Model, error) {
+	keys, err := newKeyMap(cfg.Keys)
+	if err != nil {
+		return nil, err
+	}
+	themes, themeIdx, err := newThemes(cfg)
+	if err != nil {
+		return nil, err
+	}
+	// Components cache styles, so the theme must be active before they exist
+	theme.Apply(themes[themeIdx])
+	list := clist.New()
+	list.SetKeyMap(keys)
+	tarball := components.NewTarballBrowser()
+	tarball.SetCloseKey(keys.Tarball.Help().Key)
 	// configure spinner
 	sp := spinner.New()
 	// Use a line spinner everywhere
 	sp.Spinner = spinner.Meter
 	sp.Style = lipgloss.NewStyle().Foreground(theme.Mauve)
+	status := components.NewStatusBar()
+	status.SetRegistry(registryHost())
 
 	return &Model{
-		input:      components.NewInput(),
-		list:       clist.New(),
-		side:       components.NewDetails(),
-		readme:     components.NewMarkdownViewer(),
-		focus:      focusInput,
-		spinner:    sp,
-		installing: map[string]bool{},
-		installed:  map[string]bool{},
-	}
+		input:          components.NewInput(),
+		list:           list,
+		side:           components.NewDetails(),
+		readme:         components.NewMarkdownViewer(),
+		compare:        components.NewCompare(),
+		tarball:        tarball,
+		palette:        components.NewPalette(),
+		status:         status,
+		launch:         commands.SystemLauncher,
+		readmeLabel:    "Loading readme",
+		focus:          focusInput,
+		spinner:        sp,
+		installing:     map[string]bool{},
+		installed:      map[string]bool{},
+		trends:         map[string]commands.DownloadTrend{},
+		sizes:          map[string]commands.PackageSizeMsg{},
+		sizePending:    map[string]bool{},
+		risks:          map[string]commands.RiskMsg{},
+		riskPending:    map[string]bool{},
+		squats:         map[string]commands.TyposquatMsg{},
+		squatPending:   map[string]bool{},
+		publish:        map[string]commands.PublishInfoMsg{},
+		publishPending: map[string]bool{},
+
+		licensePolicy: cfg.License,
+		licenses:      map[string]string{},
+		watchNew:      map[string]string{},
+
+		keys:           keys,
+		searchSize:     cfg.SearchSize,
+		packageManager: cfg.PackageManager,
+		themes:         themes,
+		themeIdx:       themeIdx,
+
+		chartWindow:      cfg.Window(),
+		chartGranularity: commands.GranularityWeekly,
+	}, nil
 }
 
 func (m *Model) Init() tea.Cmd {
@@ -77,7 +214,8 @@ func (m *Model) Init() tea.Cmd {
 	m.loading = true
 	m.list.SetTitle("Loading project packages…")
 	m.list.SetPlaceholder("Loading project packages…")
-	return tea.Batch(m.input.Init(), m.spinner.Tick, commands.ScanInstalledDeps(), commands.LoadProjectPackages())
+	return tea.Batch(m.input.Init(), m.spinner.Tick, commands.ScanInstalledDeps(), commands.LoadProjectPackages(),
+		commands.DetectProject(m.packageManager), m.checkOnline(0), commands.LoadHistory(), commands.LoadWatchlist())
 }
 
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -95,7 +233,13 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		// If README is loading, update its spinner label as well
 		if m.readmeOpen && m.readmeLoading {
-			m.readme.SetLoading("Loading readme", m.spinner.View())
+			m.readme.SetLoading(m.readmeLabel, m.spinner.View())
+		}
+		if m.compareOpen && m.compareLoading {
+			m.compare.SetLoading("Loading comparison", m.spinner.View())
+		}
+		if m.tarballOpen && m.tarballLoading {
+			m.tarball.SetLoading("Downloading tarball", m.spinner.View())
 		}
 		// Also update row spinner frame for installing packages
 		m.list.SetRowSpinner(m.spinner.View())
@@ -107,15 +251,64 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.applyFocus()
 		// Recenter loading state if README is loading
 		if m.readmeOpen && m.readmeLoading {
-			m.readme.SetLoading("Loading readme", m.spinner.View())
+			m.readme.SetLoading(m.readmeLabel, m.spinner.View())
 		}
 		return m, nil
 
 	case tea.KeyMsg:
+		// The command palette consumes all keys while open
+		if m.palette.IsOpen() && msg.Type != tea.KeyCtrlC {
+			return m, m.palette.Update(msg)
+		}
+		// A pending install confirmation consumes the next key
+		if m.confirm != nil && msg.Type != tea.KeyCtrlC {
+			return m, m.handleConfirmKey(msg)
+		}
+		// So do the copy and open menus
+		if m.menu != nil && msg.Type != tea.KeyCtrlC {
+			return m, m.handleMenuKey(msg)
+		}
+		// So does the name of a query being saved
+		if m.naming != nil && msg.Type != tea.KeyCtrlC {
+			return m, m.handleNamingKey(msg)
+		}
+		switch {
+		case msg.Type == tea.KeyCtrlP:
+			return m, m.palette.Open("Type to search actions", m.paletteItems())
+		case msg.Type == tea.KeyCtrlR:
+			return m, m.openQueryPicker()
+		case msg.Type == tea.KeyCtrlS && m.focus == focusInput:
+			m.startNaming()
+			return m, nil
+		}
+		// The viewer's search, outline and link keys take precedence over shortcuts
+		if m.readmeOpen && !m.readmeLoading && !m.compareOpen && !m.tarballOpen && m.readme.Captures(msg) {
+			return m, m.readme.Update(msg)
+		}
+		// The tarball browser owns navigation keys (including Enter) while open
+		if m.tarballOpen && msg.Type != tea.KeyCtrlC && msg.Type != tea.KeyEsc && !key.Matches(msg, m.keys.Tarball) {
+			return m, m.tarball.Update(msg)
+		}
 		switch msg.Type {
 		case tea.KeyCtrlC:
 			return m, tea.Quit
 		case tea.KeyEsc:
+			// Leave the tarball file viewer, then the tarball browser
+			if m.tarballOpen {
+				if !m.tarball.Back() {
+					m.tarballOpen = false
+					m.tarballLoading = false
+					m.recomputeLayout()
+				}
+				return m, nil
+			}
+			// Close the comparison view first, keeping marks intact
+			if m.compareOpen {
+				m.compareOpen = false
+				m.compareLoading = false
+				m.recomputeLayout()
+				return m, nil
+			}
 			// If README is open, close it quickly without resetting state
 			if m.readmeOpen {
 				m.readmeOpen = false
@@ -123,20 +316,8 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.recomputeLayout()
 				return m, nil
 			}
-			// Otherwise: Clear the input, return focus to it, close sidebar, and reload local packages
-			m.input.Clear()
-			m.focus = focusInput
-			m.sideOpen = false
-			m.readmeOpen = false
-			m.readmeLoading = false
-			m.applyFocus()
-			// Trigger reload of project packages
-			m.loading = true
-			m.list.SetTitle("Loading project packages…")
-			m.list.SetPlaceholder("Loading project packages…")
-			// Recompute sizes after closing sidebar
-			m.recomputeLayout()
-			return m, commands.LoadProjectPackages()
+			// Otherwise: clear the input and show the project packages
+			return m, m.showProject()
 		case tea.KeyTab:
 			// cycle focus; when sidebar is open, include it in the cycle
 			if m.sideOpen {
@@ -188,139 +369,91 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		case tea.KeyEnter:
 			if m.focus == focusInput {
-				// Move focus to results; only trigger search if query is non-empty
-				q := m.input.Value()
-				m.focus = focusResults
-				m.applyFocus()
-				if strings.TrimSpace(q) == "" {
-					// Keep current items (e.g., project packages) and do not enter loading state
+				return m, m.search(m.input.Value())
+			} else if u := m.side.SelectedLink(); m.focus == focusSide && m.sideOpen && u != "" {
+				// Open the link row selected with ] and [
+				return m, m.openURL(u)
+			} else if m.focus == focusResults || (m.focus == focusSide && m.sideOpen) {
+				// Toggle the sidebar when pressing Enter on results
+				return m, m.toggleDetails()
+			}
+		}
+		// ] and [ select link rows in the focused sidebar
+		if m.focus == focusSide && m.sideOpen && !m.readmeOpen && !m.compareOpen && !m.tarballOpen {
+			switch msg.String() {
+			case "]":
+				m.side.CycleLink(1)
+				return m, nil
+			case "[":
+				m.side.CycleLink(-1)
+				return m, nil
+			}
+		}
+		// Rune key handling; while comparing or browsing a tarball only the
+		// respective toggle applies
+		if len(msg.Runes) == 1 && (!m.compareOpen || key.Matches(msg, m.keys.Compare)) && (!m.tarballOpen || key.Matches(msg, m.keys.Tarball)) {
+			switch {
+			case key.Matches(msg, m.keys.Tarball):
+				// Toggle the tarball browser for the selected package
+				if m.tarballOpen || m.packageFocus() {
+					return m, m.toggleTarball()
+				}
+			case key.Matches(msg, m.keys.Theme):
+				if m.packageFocus() {
+					m.cycleTheme()
 					return m, nil
 				}
-				m.loading = true
-				m.list.SetTitle(m.list.RenderPrefixedTitle("Searching npm", m.spinner.View()))
-				m.list.UsePlainTitleStyle()
-				m.list.SetPlaceholder(fmt.Sprintf("Searching npm %s", m.spinner.View()))
-				return m, tea.Batch(commands.SearchNPM(q))
-			} else if m.focus == focusResults {
-				// Toggle the sidebar when pressing Enter on results
-				if m.sideOpen {
-					m.sideOpen = false
-					m.readmeOpen = false
-					m.readmeLoading = false
-					m.list.SetShowReadmeHotkey(false)
-					// return focus to results when closing
-					m.focus = focusResults
-					m.applyFocus()
-					// Recompute sizes for closed state
-					m.recomputeLayout()
+			case key.Matches(msg, m.keys.Copy):
+				if m.packageFocus() {
+					m.openCopyMenu()
 					return m, nil
 				}
-				// Open the sidebar with details for the selected item
-				m.sideOpen = true
-				// give keyboard focus to the sidebar when it is opened so its
-				// border shows active color and the list loses the active border
-				m.focus = focusSide
-				m.applyFocus()
-				m.list.SetShowReadmeHotkey(true)
-				if det, ok := m.list.SelectedDetails(); ok {
-					m.side.SetContent(det.Name, det.Description, det.Homepage, det.Repository, det.NPMLink)
-					m.side.SetStats(det.StatsLine)
+			case key.Matches(msg, m.keys.Open):
+				if m.packageFocus() {
+					m.openLinkMenu()
+					return m, nil
 				}
-				// Recompute sizes for open state
-				m.recomputeLayout()
-				// Kick off downloads range fetch for sparkline (last 365 days)
-				if name, ok := m.list.SelectedName(); ok {
-					return m, commands.FetchDownloadsRange(name, 365)
+			case key.Matches(msg, m.keys.Star):
+				if m.packageFocus() {
+					return m, m.toggleStar()
 				}
-				return m, nil
-			} else if m.focus == focusSide {
-				if m.sideOpen {
-					m.sideOpen = false
-					m.readmeOpen = false
-					m.readmeLoading = false
-					m.list.SetShowReadmeHotkey(false)
-					m.focus = focusResults
-					m.applyFocus()
-					m.recomputeLayout()
-					return m, nil
+			case key.Matches(msg, m.keys.Watchlist):
+				if m.packageFocus() {
+					return m, m.showWatchlist()
 				}
-			}
-		}
-		// Rune key handling
-		if r := msg.Runes; len(r) == 1 {
-			switch r[0] {
-			case 'i':
-				if m.focus == focusResults || m.focus == focusSide {
-					if name, ok := m.list.SelectedName(); ok {
-						// mark installing and kick off command
-						if m.installing == nil {
-							m.installing = map[string]bool{}
-						}
-						m.installing[name] = true
-						m.list.SetInstalling(m.installing)
-						return m, commands.InstallNPM(name, false)
-					}
+			case key.Matches(msg, m.keys.Mark):
+				if m.packageFocus() {
+					return m, m.toggleMark()
 				}
-			case 'I':
-				if m.focus == focusResults || m.focus == focusSide {
-					if name, ok := m.list.SelectedName(); ok {
-						if m.installing == nil {
-							m.installing = map[string]bool{}
-						}
-						m.installing[name] = true
-						m.list.SetInstalling(m.installing)
-						return m, commands.InstallNPM(name, true)
-					}
+			case key.Matches(msg, m.keys.Compare):
+				if m.compareOpen || m.packageFocus() {
+					return m, m.toggleCompare()
 				}
-			case 'u':
-				if m.focus == focusResults || m.focus == focusSide {
-					if name, ok := m.list.SelectedName(); ok {
-						if m.installing == nil {
-							m.installing = map[string]bool{}
-						}
-						m.installing[name] = true
-						m.list.SetInstalling(m.installing)
-						// Reuse install command which performs update when already installed
-						return m, commands.InstallNPM(name, false)
-					}
+			case key.Matches(msg, m.keys.Install, m.keys.Update):
+				// Update reuses install, which updates when already installed
+				if m.packageFocus() {
+					// run pre-install checks, then kick off the command
+					return m, m.installSelected(false)
 				}
-			case 'r', 'R':
-				// Only handle README toggle when results or sidebar are focused and sidebar is open
-				if (m.focus != focusResults && m.focus != focusSide) || !m.sideOpen {
-					break
+			case key.Matches(msg, m.keys.InstallDev):
+				if m.packageFocus() {
+					return m, m.installSelected(true)
 				}
-				if m.readmeOpen {
-					m.readmeOpen = false
-					m.readmeLoading = false
-					// When closing README, keep sidebar/results focus cycle intact
-					if m.sideOpen {
-						if m.focus == focusSide {
-							// keep focus on sidebar after README closes
-							m.focus = focusSide
-						} else {
-							m.focus = focusResults
-						}
-					} else {
-						m.focus = focusResults
-					}
-					m.applyFocus()
-					m.recomputeLayout()
-					return m, nil
+			case key.Matches(msg, m.keys.ChartWindow, m.keys.Granularity):
+				// Cycle the downloads chart window (w) or granularity (g)
+				if m.packageFocus() && m.sideOpen && !m.readmeOpen {
+					return m, m.cycleChart(key.Matches(msg, m.keys.ChartWindow))
 				}
-				if det, ok := m.list.SelectedDetails(); ok {
-					m.readmeOpen = true
-					m.recomputeLayout()
-					if det.Repository == "" {
-						m.readme.SetPlain("No GitHub repository URL found for this package.")
-						return m, nil
-					}
-					// Show spinner while loading the README
-					m.readmeLoading = true
-					m.readme.SetLoading("Loading readme", m.spinner.View())
-					m.readmeReq++
-					return m, commands.FetchGitHubReadmeWithReq(det.Repository, m.readmeReq)
+			case key.Matches(msg, m.keys.Changelog):
+				// Toggle the changelog between the installed and the latest version
+				if m.packageFocus() {
+					return m, m.toggleChangelog()
+				}
+			case key.Matches(msg, m.keys.Readme):
+				// Only handle README toggle when results or sidebar are focused and sidebar is open
+				if m.packageFocus() && m.sideOpen {
+					return m, m.toggleReadme()
 				}
-				return m, nil
 			}
 		}
 	case commands.NpmSearchMsg:
@@ -329,7 +462,8 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.loading = false
 			m.list.SetTitle("Results")
 			m.list.SetPlaceholder("Type and press Enter to search.")
-			return m, nil
+			// The registry may have gone away; recheck right away
+			return m, tea.Batch(m.toast(components.ToastError, "Search failed: "+msg.Err.Error()), m.checkOnline(0))
 		}
 		// Map results into list items, include weekly downloads and author
 		items := make([]clist.ItemWithMeta, 0, len(msg.Result.Objects))
@@ -338,21 +472,33 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		dlLabel := lipgloss.NewStyle().Foreground(theme.Sky).Bold(true).Render("Weekly Downloads:")
 		licLabel := lipgloss.NewStyle().Foreground(theme.Yellow).Bold(true).Render("License:")
 		autLabel := lipgloss.NewStyle().Foreground(theme.Mauve).Bold(true).Render("Author:")
+		violations := map[string]string{}
 		for _, o := range msg.Result.Objects {
 			title := o.Package.Name
+			m.licenses[title] = o.Package.License
+			if v := m.licensePolicy.Check(o.Package.License); v.Violation {
+				violations[title] = nonEmpty(o.Package.License)
+			}
 			line := fmt.Sprintf("%s %s  %s %s  %s %s  %s %s", verLabel, o.Package.Version, dlLabel, fmtInt(o.Package.DownloadsLastWeek), licLabel, nonEmpty(o.Package.License), autLabel, nonEmpty(o.Package.Author))
 			full := o.Package.Description
 			home := o.Package.Links.Homepage
 			repo := o.Package.Links.Repository
 			npm := o.Package.Links.NPM
-			items = append(items, clist.ItemWithMeta{Title: title, LineDesc: line, FullDesc: full, Homepage: home, Repository: repo, NPMLink: npm, Latest: o.Package.Version})
+			items = append(items, clist.ItemWithMeta{
+				Title: title, LineDesc: line, FullDesc: full, Homepage: home, Repository: repo, NPMLink: npm, Bugs: o.Package.Links.Bugs, Latest: o.Package.Version,
+				Deprecated: o.Package.Deprecated, InstalledVersion: o.Package.InstalledVersion, InstalledDeprecated: o.Package.InstalledDeprecated,
+			})
 		}
+		m.list.SetLicenseViolations(violations)
 		m.loading = false
 		// restore default title style (with lavender background) for regular titles
 		m.list.UseDefaultTitleStyle()
 		// send items with metadata for sidebar
 		// convert to the specialized setter to preserve extra fields
-		if msg.Query == "" {
+		if msg.Watchlist {
+			m.list.SetItemsWithMeta("Watchlist", items)
+			m.list.SetPlaceholder("Press Enter for details, " + bindingKey(m.keys.Star) + " to unstar. Esc shows project packages.")
+		} else if msg.Query == "" {
 			m.list.SetItemsWithMeta("Project packages", items)
 			if len(items) == 0 {
 				m.list.SetTitle("Project packages")
@@ -371,21 +517,57 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.readmeOpen = false
 		m.readmeLoading = false
 		m.list.SetShowReadmeHotkey(false)
-		m.side.SetContent("", "", "", "", "")
-		m.side.SetStats("")
+		m.showDetails(clist.Details{})
 		m.side.SetDownloadsValues(nil)
 		// ensure sizing is recomputed on new data
 		m.recomputeLayout()
 		// initialize sidebar with first selection, if any
 		if det, ok := m.list.SelectedDetails(); ok {
-			m.side.SetContent(det.Name, det.Description, det.Homepage, det.Repository, det.NPMLink)
-			m.side.SetStats(det.StatsLine)
+			m.showDetails(det)
 		} else {
-			m.side.SetContent("", "", "", "", "")
-			m.side.SetStats("")
+			m.showDetails(clist.Details{})
+		}
+		// refresh installed marks against current package.json and fetch
+		// download trends for the new rows
+		names := make([]string, 0, len(items))
+		for _, it := range items {
+			names = append(names, it.Title)
+		}
+		return m, tea.Batch(commands.ScanInstalledDeps(), commands.FetchDownloadTrends(names, commands.TrendRowWindow), m.noteNewVersions(m.list.LatestVersions(), msg.Watchlist))
+	case commands.PackageSizeMsg:
+		if errors.Is(msg.Err, context.Canceled) {
+			// the selection moved on; fetchSideData already cleaned up
+			return m, nil
 		}
-		// refresh installed marks against current package.json
-		return m, commands.ScanInstalledDeps()
+		if msg.Package == m.sizeFetching {
+			m.sizeCancel()
+			m.sizeFetching, m.sizeCancel = "", nil
+		}
+		delete(m.sizePending, msg.Package)
+		if msg.Err == nil {
+			m.sizes[msg.Package] = msg
+		}
+		if det, ok := m.list.SelectedDetails(); ok && det.Name == msg.Package {
+			m.showDetails(det)
+		}
+		return m, nil
+	case commands.DownloadTrendsMsg:
+		for k, v := range msg.Trends {
+			// Keep the selected package's longer trend over a later row refresh
+			if old, ok := m.trends[k]; ok && old.Window > v.Window {
+				continue
+			}
+			m.trends[k] = v
+		}
+		dirs := make(map[string]string, len(m.trends))
+		for k, v := range m.trends {
+			dirs[k] = v.Direction.String()
+		}
+		m.list.SetTrends(dirs)
+		if det, ok := m.list.SelectedDetails(); ok {
+			m.showDetails(det)
+		}
+		return m, nil
 	case commands.ScanDepsMsg:
 		if msg.Installed != nil {
 			// merge known installed from scans with runtime installs
@@ -401,13 +583,38 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// provide wanted (manifest) versions for update detection
 			m.list.SetWantedVersions(msg.Wanted)
 		}
+		if msg.Err != nil {
+			return m, m.toast(components.ToastWarning, "Could not read package.json: "+msg.Err.Error())
+		}
+		return m, nil
+	case commands.RiskMsg:
+		delete(m.riskPending, msg.Package)
+		m.risks[msg.Package] = msg
+		if det, ok := m.list.SelectedDetails(); ok && det.Name == msg.Package {
+			m.side.SetRisk(riskSignals(msg))
+		}
+		return m, m.resumeInstall(msg.Package)
+	case commands.PublishInfoMsg:
+		delete(m.publishPending, msg.Package)
+		m.publish[msg.Package] = msg
+		if det, ok := m.list.SelectedDetails(); ok && det.Name == msg.Package {
+			m.showDetails(det)
+		}
 		return m, nil
+	case commands.TyposquatMsg:
+		delete(m.squatPending, msg.Package)
+		m.squats[msg.Package] = msg
+		if det, ok := m.list.SelectedDetails(); ok && det.Name == msg.Package {
+			m.side.SetLikelyIntended(likelyIntended(msg))
+		}
+		return m, m.resumeInstall(msg.Package)
 	case commands.NpmInstallMsg:
 		// clear installing flag for the package
 		if msg.Package != "" && m.installing != nil {
 			delete(m.installing, msg.Package)
 			m.list.SetInstalling(m.installing)
 		}
+		toast := m.toast(installToast(msg, m.installed[msg.Package]))
 		// mark success (no error) to show checkmark
 		if msg.Package != "" && msg.Err == nil {
 			if m.installed == nil {
@@ -416,9 +623,9 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.installed[msg.Package] = true
 			m.list.SetInstalled(m.installed)
 			// rescan package.json to refresh installed and wanted versions
-			return m, commands.ScanInstalledDeps()
+			return m, tea.Batch(toast, commands.ScanInstalledDeps())
 		}
-		return m, nil
+		return m, toast
 	case commands.GitHubReadmeMsg:
 		// Render markdown asynchronously for responsiveness
 		if msg.Err != nil {
@@ -439,13 +646,150 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Render synchronously so the formatted output shows immediately
 		if m.readmeOpen {
 			m.readmeLoading = false
-			m.readme.SetMarkdown(msg.Content)
+			m.readmeRepo, m.readmeDir = msg.Repo, msg.Dir
+			blobRoot, rawRoot := commands.RepoBaseURLs(msg.Repo)
+			m.readme.SetBase(blobRoot, rawRoot, msg.Dir)
+			m.readme.SetDocument(msg.Content, msg.Filename)
 			// Ensure viewport processes the new content immediately
 			if cmd := m.readme.Update(nil); cmd != nil {
 				return m, cmd
 			}
 		}
 		return m, nil
+	case components.ReadmeLinkMsg:
+		if !m.readmeOpen {
+			return m, nil
+		}
+		if commands.UnsupportedLink(msg.Href) {
+			return m, m.toast(components.ToastError, "Unsupported link scheme")
+		}
+		browserURL, rawURL, repoPath := commands.ResolveRepoLink(m.readmeRepo, m.readmeDir, msg.Href)
+		// Markdown files in the same repository open in the viewer itself
+		if rawURL != "" {
+			m.viewerChangelog = false
+			m.readmeLoading = true
+			m.readmeLabel = "Loading " + path.Base(repoPath)
+			m.readme.SetLoading(m.readmeLabel, m.spinner.View())
+			m.readmeReq++
+			return m, commands.FetchRepoDocument(m.readmeRepo, repoPath, rawURL, m.readmeReq)
+		}
+		if browserURL != "" {
+			return m, m.openURL(browserURL)
+		}
+		return m, nil
+	case commands.ChangelogMsg:
+		// Ignore stale responses and changelogs arriving after the viewer switched
+		if msg.Req != m.readmeReq || !m.readmeOpen || !m.viewerChangelog {
+			return m, nil
+		}
+		m.readmeLoading = false
+		m.readmeRepo, m.readmeDir = "", ""
+		m.readme.SetBase("", "", "")
+		if msg.Err != nil {
+			m.readme.SetPlain("Could not load changelog:\n\n" + msg.Err.Error())
+		} else {
+			m.readme.SetMarkdown(msg.Markdown)
+		}
+		return m, m.readme.Update(nil)
+	case commands.CompareMsg:
+		if !m.compareOpen || msg.Req != m.compareReq {
+			// closed, or superseded by a newer comparison
+			return m, nil
+		}
+		m.compareLoading = false
+		cols := make([]components.CompareColumn, 0, len(msg.Packages))
+		for _, p := range msg.Packages {
+			col := components.CompareColumn{
+				Name:            p.Name,
+				Version:         p.Version,
+				WeeklyDownloads: p.WeeklyDownloads,
+				License:         p.License,
+				UnpackedSize:    p.UnpackedSize,
+				Dependencies:    p.Dependencies,
+				LastPublish:     p.LastPublish,
+				Maintainers:     p.Maintainers,
+				TypeScript:      p.TypeScript,
+			}
+			for _, pt := range p.Downloads {
+				col.Times = append(col.Times, pt.Time)
+				col.Values = append(col.Values, pt.Value)
+			}
+			if p.Err != nil {
+				col.Err = p.Err.Error()
+			}
+			cols = append(cols, col)
+		}
+		m.compare.SetColumns(cols)
+		return m, nil
+	case commands.TarballMsg:
+		// Ignore tarballs for a package the browser no longer shows
+		if !m.tarballOpen || msg.Package != m.tarballPkg {
+			return m, nil
+		}
+		m.tarballLoading = false
+		if msg.Err != nil {
+			m.tarball.SetError(msg.Err)
+			return m, nil
+		}
+		m.tarballArchive = msg.Archive
+		files := make([]components.TarballEntry, 0, len(msg.Files))
+		for _, f := range msg.Files {
+			files = append(files, components.TarballEntry{Path: f.Path, Size: f.Size})
+		}
+		m.tarball.SetFiles(msg.Package, msg.Version, files)
+		return m, nil
+	case commands.CopyMsg:
+		return m, m.toast(copyToast(msg))
+	case commands.CopyViaTerminalMsg:
+		return m, commands.CopyViaTerminal(msg.Text, msg.What)
+	case commands.OpenURLMsg:
+		return m, m.toast(openToast(msg))
+	case commands.ToastMsg:
+		return m, m.toast(toastLevels[msg.Level], msg.Text)
+	case components.ToastExpiredMsg:
+		return m, m.status.Update(msg)
+	case commands.ProjectInfoMsg:
+		return m, m.setProject(msg)
+	case commands.ConnectivityMsg:
+		m.status.SetOnline(msg.Online)
+		if msg.Req != m.onlineReq {
+			return m, nil
+		}
+		return m, m.checkOnline(onlinePollInterval)
+	case commands.WatchlistMsg:
+		if msg.Err != nil {
+			return m, m.toast(components.ToastWarning, "Could not read watchlist; stars will not be saved: "+msg.Err.Error())
+		}
+		save := m.loadWatchlist(msg.Watchlist)
+		// Badge new versions of watched packages already listed
+		return m, tea.Batch(save, m.noteNewVersions(m.list.LatestVersions(), false))
+	case commands.WatchlistSavedMsg:
+		if msg.Err != nil {
+			return m, m.toast(components.ToastWarning, "Could not save watchlist: "+msg.Err.Error())
+		}
+		return m, nil
+	case commands.HistoryMsg:
+		if msg.Err != nil {
+			return m, m.toast(components.ToastWarning, "Could not read search history; searches will not be saved: "+msg.Err.Error())
+		}
+		return m, m.loadHistory(msg.History)
+	case commands.HistorySavedMsg:
+		if msg.Err != nil {
+			return m, m.toast(components.ToastWarning, "Could not save search history: "+msg.Err.Error())
+		}
+		return m, nil
+	case components.PaletteRunMsg:
+		return m, m.runPaletteAction(msg.ID)
+	case components.TarballOpenMsg:
+		if !m.tarballOpen || m.tarballArchive == "" {
+			return m, nil
+		}
+		return m, commands.ReadTarballEntry(m.tarballArchive, msg.Path)
+	case commands.TarballFileMsg:
+		if msg.Archive == m.tarballArchive {
+			m.tarball.SetFileContent(msg.Path, msg.Content, msg.Err)
+		}
+		return m, nil
 	case components.MarkdownRenderedMsg:
 		// Ignore stale renders not matching the current request
 		if msg.Seq != m.readmeReq {
@@ -460,6 +804,18 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	// Let the focused component handle the message.
 	var cmds []tea.Cmd
+	// The comparison view and tarball browser take over input while open
+	if m.compareOpen {
+		switch msg.(type) {
+		case tea.KeyMsg, tea.MouseMsg:
+			return m, m.compare.Update(msg)
+		}
+	}
+	if m.tarballOpen {
+		if _, ok := msg.(tea.MouseMsg); ok {
+			return m, m.tarball.Update(msg)
+		}
+	}
 	// Input routing to ensure correct scrolling behavior
 	if !m.readmeOpen {
 		switch t := msg.(type) {
@@ -484,16 +840,19 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			// Update sidebar content if selection moved
 			if det, ok := m.list.SelectedDetails(); ok {
-				m.side.SetContent(det.Name, det.Description, det.Homepage, det.Repository, det.NPMLink)
-				m.side.SetStats(det.StatsLine)
+				m.showDetails(det)
 				if m.sideOpen {
 					if name, ok2 := m.list.SelectedName(); ok2 {
-						cmds = append(cmds, commands.FetchDownloadsRange(name, 365))
+						cmds = append(cmds, m.fetchSideData(name))
 					}
 				}
 			}
 			return m, tea.Batch(cmds...)
 		case tea.KeyMsg:
+			// Up and Down recall past searches in the input
+			if m.focus == focusInput && (t.Type == tea.KeyUp || t.Type == tea.KeyDown) {
+				return m, m.input.Update(msg)
+			}
 			switch t.Type {
 			case tea.KeyUp, tea.KeyDown, tea.KeyPgUp, tea.KeyPgDown, tea.KeyHome, tea.KeyEnd:
 				// When the sidebar is open we should prefer scrolling it only when it
@@ -512,11 +871,10 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				// Update sidebar content as selection changes
 				if det, ok := m.list.SelectedDetails(); ok {
-					m.side.SetContent(det.Name, det.Description, det.Homepage, det.Repository, det.NPMLink)
-					m.side.SetStats(det.StatsLine)
+					m.showDetails(det)
 					if m.sideOpen {
 						if name, ok2 := m.list.SelectedName(); ok2 {
-							cmds = append(cmds, commands.FetchDownloadsRange(name, 365))
+							cmds = append(cmds, m.fetchSideData(name))
 						}
 					}
 				}
@@ -539,11 +897,10 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmds = append(cmds, cmd)
 		}
 		if det, ok := m.list.SelectedDetails(); ok {
-			m.side.SetContent(det.Name, det.Description, det.Homepage, det.Repository, det.NPMLink)
-			m.side.SetStats(det.StatsLine)
+			m.showDetails(det)
 			if m.sideOpen {
 				if name, ok2 := m.list.SelectedName(); ok2 {
-					cmds = append(cmds, commands.FetchDownloadsRange(name, 365))
+					cmds = append(cmds, m.fetchSideData(name))
 				}
 			}
 		}
@@ -591,7 +948,10 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// Handle async downloads-range results outside the large switch for clarity
 	switch msg := msg.(type) {
 	case commands.NpmDownloadsRangeMsg:
-		if msg.Err == nil && msg.Package != "" {
+		// Ignore responses for another selection or a previous window/granularity
+		name, _ := m.list.SelectedName()
+		stale := msg.Package != name || msg.Window != m.chartWindow || msg.Granularity != m.chartGranularity
+		if msg.Err == nil && msg.Package != "" && !stale {
 			m.side.SetDownloadsValues(msg.Values)
 			if len(msg.Points) > 0 {
 				ts := make([]time.Time, 0, len(msg.Points))
@@ -612,17 +972,40 @@ func (m *Model) View() string {
 		return "" // wait for initial size
 	}
 	// Render input and results. The input renders its own inline label.
-	m.input.SetLabel("npm-search:", lipgloss.NewStyle().Foreground(theme.Subtext0))
+	if m.naming != nil {
+		m.input.SetLabel("save query as:", lipgloss.NewStyle().Foreground(theme.Subtext0))
+	} else {
+		m.input.SetLabel("npm-search:", lipgloss.NewStyle().Foreground(theme.Subtext0))
+	}
 	inputView := m.input.View()
 	// When README is open, use the full area below the input
 	var body string
-	if m.readmeOpen {
+	if m.compareOpen {
+		body = m.compare.View()
+	} else if m.tarballOpen {
+		body = m.tarball.View()
+	} else if m.readmeOpen {
 		body = m.readme.View()
 	} else {
 		// Two-column layout: list + sidebar
 		body = lipgloss.JoinHorizontal(lipgloss.Top, m.list.View(), m.side.View())
 	}
-	return lipgloss.JoinVertical(lipgloss.Left, inputView, body)
+	var view string
+	if m.confirm != nil {
+		view = lipgloss.JoinVertical(lipgloss.Left, inputView, m.confirmView(), body)
+	} else if m.menu != nil {
+		view = lipgloss.JoinVertical(lipgloss.Left, inputView, m.menuView(), body)
+	} else {
+		view = lipgloss.JoinVertical(lipgloss.Left, inputView, body)
+	}
+	view = lipgloss.JoinVertical(lipgloss.Left, view, m.status.View())
+	if m.palette.IsOpen() {
+		// Float the palette below the input, horizontally centered
+		pv := m.palette.View()
+		x := max(0, (m.width-lipgloss.Width(pv))/2)
+		view = components.Overlay(view, pv, x, m.input.Height())
+	}
+	return view
 }
 
 // Helpers
@@ -642,6 +1025,10 @@ func (m *Model) applyFocus() {
 
 	// Sidebar shows active border only when it has keyboard focus.
 	m.side.SetFocused(m.focus == focusSide)
+	// Link rows are only selected while the sidebar has focus
+	if m.focus != focusSide {
+		m.side.ClearLinkSelection()
+	}
 }
 
 //
@@ -650,10 +1037,18 @@ func (m *Model) applyFocus() {
 func (m *Model) recomputeLayout() {
 	m.input.SetWidth(m.width)
 	// Height remaining for list/sidebar
-	remaining := m.height - m.input.Height()
+	remaining := m.height - m.input.Height() - m.status.Height()
+	if m.confirm != nil || m.menu != nil {
+		// one line for the install confirmation prompt or the copy menu
+		remaining--
+	}
 	if remaining < 0 {
 		remaining = 0
 	}
+	m.compare.SetSize(m.width, remaining)
+	m.tarball.SetSize(m.width, remaining)
+	m.palette.SetSize(m.width, m.height)
+	m.status.SetWidth(m.width)
 	if m.readmeOpen {
 		// Full width for README viewer
 		m.readme.SetSize(m.width, remaining)
@@ -668,4 +1063,118 @@ func (m *Model) recomputeLayout() {
 	m.side.SetSize(sideW, remaining)
 }
 
+// showDetails fills the sidebar with the given list details and any
+// per-package data fetched separately (e.g., download trends).
+func (m *Model) showDetails(det clist.Details) {
+	m.side.SetContent(det.Name, det.Description, det.Homepage, det.Repository, det.NPMLink, issuesURL(det))
+	m.side.SetStats(det.StatsLine)
+	m.side.SetDeprecation(det.Deprecated, det.InstalledVersion, det.InstalledDeprecated)
+	if t, ok := m.trends[det.Name]; ok && det.Name != "" {
+		m.side.SetTrend(&components.TrendStats{
+			WeekOverWeek:    t.WeekOverWeek,
+			HasWeekOverWeek: t.HasWeekOverWeek,
+			YearOverYear:    t.YearOverYear,
+			HasYearOverYear: t.HasYearOverYear,
+			PeakWeek:        t.PeakWeek,
+			PeakValue:       t.PeakValue,
+			MovingAverage:   t.MovingAverage,
+			Direction:       t.Direction.String(),
+		})
+	} else {
+		m.side.SetTrend(nil)
+	}
+	if sz, ok := m.sizes[det.Name]; ok {
+		m.side.SetFootprint(&components.Footprint{
+			UnpackedSize:  sz.UnpackedSize,
+			FileCount:     sz.FileCount,
+			Dependencies:  sz.Dependencies,
+			TotalSize:     sz.TotalSize,
+			TotalPackages: sz.TotalPackages,
+			Partial:       sz.Partial,
+		})
+	} else if m.sizePending[det.Name] {
+		m.side.SetFootprint(&components.Footprint{Loading: true})
+	} else {
+		m.side.SetFootprint(nil)
+	}
+	if p, ok := m.publish[det.Name]; ok && p.Err == nil {
+		m.side.SetPublishing(&components.Publishing{
+			Version:           p.Version,
+			Maintainers:       p.Maintainers,
+			Publisher:         p.Publisher,
+			PublishedAt:       p.PublishedAt,
+			Provenance:        p.Provenance,
+			Builder:           p.Builder,
+			SourceRepo:        p.SourceRepo,
+			SourceCommit:      p.SourceCommit,
+			CommitURL:         p.CommitURL,
+			Signed:            p.Signed,
+			SignatureVerified: p.SignatureVerified,
+		})
+	} else if m.publishPending[det.Name] {
+		m.side.SetPublishing(&components.Publishing{Loading: true})
+	} else {
+		m.side.SetPublishing(nil)
+	}
+	m.side.SetLicenseIssue(m.licenseIssue(det.Name))
+	if sq, ok := m.squats[det.Name]; ok {
+		m.side.SetLikelyIntended(likelyIntended(sq))
+	} else {
+		m.side.SetLikelyIntended("")
+	}
+	if r, ok := m.risks[det.Name]; ok {
+		m.side.SetRisk(riskSignals(r))
+	} else if m.riskPending[det.Name] {
+		m.side.SetRisk(&components.RiskSignals{Loading: true})
+	} else {
+		m.side.SetRisk(nil)
+	}
+}
+
+// fetchSideData requests the data the open sidebar shows for name that is
+// not part of the list row: the downloads chart and the install footprint.
+// A footprint walk still running for another package is cancelled.
+func (m *Model) fetchSideData(name string) tea.Cmd {
+	cmds := []tea.Cmd{m.fetchDownloads(name)}
+	if m.sizeCancel != nil && m.sizeFetching != name {
+		m.sizeCancel()
+		delete(m.sizePending, m.sizeFetching)
+		m.sizeFetching, m.sizeCancel = "", nil
+	}
+	if _, ok := m.sizes[name]; !ok && !m.sizePending[name] {
+		m.sizePending[name] = true
+		m.side.SetFootprint(&components.Footprint{Loading: true})
+		var ctx context.Context
+		ctx, m.sizeCancel = context.WithCancel(context.Background())
+		m.sizeFetching = name
+		cmds = append(cmds, commands.FetchPackageSize(ctx, name))
+	}
+	if cmd := m.fetchRisk(name); cmd != nil {
+		m.side.SetRisk(&components.RiskSignals{Loading: true})
+		cmds = append(cmds, cmd)
+	}
+	if _, ok := m.publish[name]; !ok && !m.publishPending[name] {
+		m.publishPending[name] = true
+		m.side.SetPublishing(&components.Publishing{Loading: true})
+		cmds = append(cmds, commands.FetchPublishInfo(name))
+	}
+	// Rows only fetch a year of downloads; the sidebar shows two
+	if t, ok := m.trends[name]; !ok || t.Window < commands.Window2Years {
+		cmds = append(cmds, commands.FetchDownloadTrends([]string{name}, commands.Window2Years))
+	}
+	cmds = append(cmds, m.checkTyposquat(name))
+	return tea.Batch(cmds...)
+}
+
+// fetchDownloads requests the sidebar chart series for name using the
+// currently selected window and granularity.
+func (m *Model) fetchDownloads(name string) tea.Cmd {
+	return commands.FetchDownloadsRange(name, m.chartWindow, m.chartGranularity)
+}
+
+// chartLabel describes the current chart window and granularity.
+func (m *Model) chartLabel() string {
+	return fmt.Sprintf("Downloads: %s · %s", m.chartWindow, m.chartGranularity)
+}
+
 // fmtInt formats an int with thousand separators; moved to helpers.go
@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"fmt"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/fredrikmwold/npm-tui/internal/commands"
+)
+
+func TestToggleMarkReportsFullCompare(t *testing.T) {
+	m, err := New(commands.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	var objs []commands.NpmSearchObject
+	for i := range maxCompare + 1 {
+		var obj commands.NpmSearchObject
+		obj.Package.Name = fmt.Sprintf("pkg-%d", i)
+		obj.Package.Version = "1.0.0"
+		objs = append(objs, obj)
+	}
+	m.Update(commands.NpmSearchMsg{Query: "pkg", Result: commands.NpmSearchResult{Objects: objs}})
+	m.focus = focusResults
+
+	for i := range maxCompare + 1 {
+		if name, _ := m.list.SelectedName(); name != objs[i].Package.Name {
+			t.Fatalf("selected %q, want %q", name, objs[i].Package.Name)
+		}
+		cmd := m.toggleMark()
+		if full := i == maxCompare; full != (cmd != nil) {
+			t.Errorf("marking package %d: toast = %v, want %v", i+1, cmd != nil, full)
+		}
+		m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	if n := len(m.list.Marked()); n != maxCompare {
+		t.Errorf("marked %d packages, want %d", n, maxCompare)
+	}
+}
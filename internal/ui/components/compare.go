@@ -0,0 +1,244 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/NimbleMarkets/ntcharts/linechart/timeserieslinechart"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/fredrikmwold/npm-tui/internal/ui/theme"
+)
+
+// CompareColumn is one package column in the comparison table.
+type CompareColumn struct {
+	Name            string
+	Version         string
+	WeeklyDownloads int
+	License         string
+	UnpackedSize    int64
+	Dependencies    int
+	LastPublish     time.Time
+	Maintainers     []string
+	TypeScript      string
+	// download trend, ordered oldest..newest
+	Times  []time.Time
+	Values []float64
+	Err    string
+}
+
+// CompareModel renders a side-by-side table of packages with their download
+// trends overlaid on a single chart.
+type CompareModel struct {
+	width  int
+	height int
+	vp     viewport.Model
+	cols   []CompareColumn
+}
+
+// compareColors assigns one accent per compared package (max 4).
+func compareColors() []lipgloss.Color {
+	return []lipgloss.Color{theme.Blue, theme.Peach, theme.Green, theme.Mauve}
+}
+
+func NewCompare() *CompareModel {
+	vp := viewport.New(0, 0)
+	vp.MouseWheelEnabled = true
+	vp.Style = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(theme.BorderFocused).Foreground(theme.Text)
+	return &CompareModel{vp: vp}
+}
+
+func (c *CompareModel) Init() tea.Cmd { return nil }
+
+// ApplyTheme restyles the frame and re-renders the comparison.
+func (c *CompareModel) ApplyTheme() {
+	c.vp.Style = c.vp.Style.BorderForeground(theme.BorderFocused).Foreground(theme.Text)
+	if len(c.cols) > 0 {
+		c.vp.SetContent(c.render())
+	}
+}
+
+func (c *CompareModel) SetSize(w, h int) {
+	if w < 1 {
+		w = 1
+	}
+	if h < 0 {
+		h = 0
+	}
+	c.width, c.height = w, h
+	// viewport dimensions include the border frame
+	c.vp.Width = w
+	c.vp.Height = h
+	if len(c.cols) > 0 {
+		c.vp.SetContent(c.render())
+	}
+}
+
+// SetColumns replaces the compared packages and re-renders.
+func (c *CompareModel) SetColumns(cols []CompareColumn) {
+	c.cols = cols
+	c.vp.SetContent(c.render())
+	c.vp.GotoTop()
+}
+
+// SetLoading shows a centered loading message with a spinner.
+func (c *CompareModel) SetLoading(label, spin string) {
+	w, h := c.innerSize()
+	msg := label
+	if spin != "" {
+		msg = msg + " " + spin
+	}
+	content := lipgloss.NewStyle().Foreground(theme.Subtext0).Render(msg)
+	c.vp.SetContent(lipgloss.Place(w, h, lipgloss.Center, lipgloss.Center, content))
+}
+
+func (c *CompareModel) Update(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	c.vp, cmd = c.vp.Update(msg)
+	return cmd
+}
+
+func (c *CompareModel) View() string { return c.vp.View() }
+
+// innerSize returns the content area inside the border.
+func (c *CompareModel) innerSize() (int, int) {
+	fw, fh := c.vp.Style.GetFrameSize()
+	return intMax(1, c.vp.Width-fw), intMax(1, c.vp.Height-fh)
+}
+
+func (c *CompareModel) render() string {
+	innerW, _ := c.innerSize()
+	if len(c.cols) == 0 {
+		return ""
+	}
+	headingStyle := lipgloss.NewStyle().Foreground(theme.Crust).Background(theme.Lavender).Bold(true).Padding(0, 1)
+	labelStyle := lipgloss.NewStyle().Foreground(theme.Subtext0).Bold(true)
+	mutedStyle := lipgloss.NewStyle().Foreground(theme.Surface2)
+
+	const labelW = 18
+	colW := intMax(8, (innerW-labelW)/len(c.cols))
+	cell := func(s string, st lipgloss.Style) string {
+		return st.Width(colW).Render(truncate(s, colW-1))
+	}
+
+	var b strings.Builder
+	b.WriteString(headingStyle.Render("Compare"))
+	b.WriteString("\n\n")
+
+	// Header row with one accent color per package
+	b.WriteString(lipgloss.NewStyle().Width(labelW).Render(""))
+	for i, col := range c.cols {
+		st := lipgloss.NewStyle().Foreground(compareColors()[i%len(compareColors())]).Bold(true)
+		b.WriteString(cell(col.Name, st))
+	}
+	b.WriteString("\n")
+	b.WriteString(mutedStyle.Render(strings.Repeat("─", innerW)))
+	b.WriteString("\n")
+
+	row := func(label string, value func(CompareColumn) string) {
+		b.WriteString(labelStyle.Width(labelW).Render(label))
+		for _, col := range c.cols {
+			v := "n/a"
+			if col.Err != "" {
+				v = "error"
+			} else if s := value(col); s != "" {
+				v = s
+			}
+			b.WriteString(cell(v, lipgloss.NewStyle().Foreground(theme.Text)))
+		}
+		b.WriteString("\n")
+	}
+	row("Version", func(p CompareColumn) string { return p.Version })
+	row("Weekly downloads", func(p CompareColumn) string { return compactNumber(float64(p.WeeklyDownloads)) })
+	row("License", func(p CompareColumn) string { return p.License })
+	row("Unpacked size", func(p CompareColumn) string {
+		if p.UnpackedSize <= 0 {
+			return ""
+		}
+		return formatBytes(p.UnpackedSize)
+	})
+	row("Dependencies", func(p CompareColumn) string { return fmt.Sprintf("%d", p.Dependencies) })
+	row("Last publish", func(p CompareColumn) string {
+		if p.LastPublish.IsZero() {
+			return ""
+		}
+		return p.LastPublish.Format("2006-01-02")
+	})
+	row("Maintainers", func(p CompareColumn) string {
+		switch n := len(p.Maintainers); {
+		case n == 0:
+			return ""
+		case n <= 2:
+			return strings.Join(p.Maintainers, ", ")
+		default:
+			return fmt.Sprintf("%s +%d", strings.Join(p.Maintainers[:2], ", "), n-2)
+		}
+	})
+	row("TypeScript", func(p CompareColumn) string { return p.TypeScript })
+
+	if chart := c.renderChart(innerW); chart != "" {
+		b.WriteString("\n")
+		b.WriteString(headingStyle.Render("Weekly downloads (1y)"))
+		b.WriteString("\n\n")
+		b.WriteString(chart)
+		b.WriteString("\n")
+		// Legend mapping colors to packages
+		for i, col := range c.cols {
+			st := lipgloss.NewStyle().Foreground(compareColors()[i%len(compareColors())])
+			b.WriteString(st.Render("━━ " + col.Name))
+			b.WriteString("  ")
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// renderChart overlays every column's download trend on one time series chart.
+func (c *CompareModel) renderChart(w int) string {
+	var minT, maxT time.Time
+	points := 0
+	for _, col := range c.cols {
+		for _, t := range col.Times {
+			if minT.IsZero() || t.Before(minT) {
+				minT = t
+			}
+			if t.After(maxT) {
+				maxT = t
+			}
+			points++
+		}
+	}
+	if points < 2 || !maxT.After(minT) {
+		return ""
+	}
+	_, innerH := c.innerSize()
+	h := intMax(8, innerH-16)
+	axisStyle := lipgloss.NewStyle().Foreground(theme.Surface2)
+	labelStyle := lipgloss.NewStyle().Foreground(theme.Subtext0)
+	lc := timeserieslinechart.New(
+		w, h,
+		timeserieslinechart.WithYLabelFormatter(func(i int, v float64) string { return compactNumber(v) }),
+		timeserieslinechart.WithXLabelFormatter(func(i int, v float64) string {
+			return time.Unix(int64(v), 0).UTC().Format("Jan '06")
+		}),
+		timeserieslinechart.WithAxesStyles(axisStyle, labelStyle),
+	)
+	lc.SetTimeRange(minT, maxT)
+	lc.SetViewTimeRange(minT, maxT)
+	names := make([]string, 0, len(c.cols))
+	for i, col := range c.cols {
+		if len(col.Times) != len(col.Values) || len(col.Times) == 0 {
+			continue
+		}
+		lc.SetDataSetStyle(col.Name, lipgloss.NewStyle().Foreground(compareColors()[i%len(compareColors())]))
+		for j := range col.Values {
+			lc.PushDataSet(col.Name, timeserieslinechart.TimePoint{Time: col.Times[j], Value: col.Values[j]})
+		}
+		names = append(names, col.Name)
+	}
+	lc.DrawBrailleDataSets(names)
+	return lc.View()
+}
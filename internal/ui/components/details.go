@@ -2,7 +2,6 @@ package components
 
 import (
 	"fmt"
-	"math"
 	"regexp"
 	"strings"
 	"time"
@@ -30,10 +29,32 @@ type DetailsModel struct {
 	homepage    string
 	repository  string
 	npmLink     string
+	bugs        string
+	// selected link row (-1 for none) and whether to scroll it into view
+	linkSel    int
+	linkScroll bool
 
 	// downloads over time series
 	dlValues []float64
 	dlTimes  []time.Time
+	// label describing the chart window/granularity
+	chartLabel string
+	// computed trend stats shown under the chart (nil when unknown)
+	trend *TrendStats
+	// package size and install footprint (nil when unknown)
+	footprint *Footprint
+	// supply-chain risk signals for the latest version (nil when unknown)
+	risk *RiskSignals
+	// maintainers, publisher and provenance (nil when unknown)
+	publishing *Publishing
+	// popular package this name likely typosquats, with the reason
+	likelyIntended string
+	// license policy violation of the package
+	licenseIssue string
+	// deprecation messages for the latest and the installed version
+	deprecated          string
+	installedVersion    string
+	installedDeprecated string
 	// cached rendered chart string for current width
 	dlRendered string
 	// cached content string and dirty flag
@@ -44,6 +65,64 @@ type DetailsModel struct {
 	lastTitle string
 }
 
+// TrendStats summarizes the weekly downloads trend shown under the chart.
+type TrendStats struct {
+	WeekOverWeek    float64
+	HasWeekOverWeek bool
+	YearOverYear    float64
+	HasYearOverYear bool
+	PeakWeek        time.Time
+	PeakValue       float64
+	MovingAverage   float64
+	// Direction is "rising", "stable" or "declining" (empty when unknown)
+	Direction string
+}
+
+// Footprint describes how heavy a package is to install.
+type Footprint struct {
+	UnpackedSize int64
+	FileCount    int
+	Dependencies int
+	// estimated size of the package plus its transitive dependencies
+	TotalSize     int64
+	TotalPackages int
+	Partial       bool
+	// Loading is set while the dependency closure is being resolved
+	Loading bool
+}
+
+// RiskSignals are supply-chain warning signs for the latest version.
+type RiskSignals struct {
+	Version string
+	// InstallScripts lists "name: command" for scripts npm runs on install
+	InstallScripts []string
+	Publisher      string
+	NewPublisher   bool
+	PublishedAt    time.Time
+	Recent         bool
+	Provenance     bool
+	// Loading is set while the signals are being fetched
+	Loading bool
+}
+
+// Publishing describes the maintainers and the publish of the latest version.
+type Publishing struct {
+	Version     string
+	Maintainers []string
+	Publisher   string
+	PublishedAt time.Time
+	Provenance  bool
+	Builder     string
+	SourceRepo  string
+	// SourceCommit is the commit the version was built from; CommitURL links to it
+	SourceCommit      string
+	CommitURL         string
+	Signed            bool
+	SignatureVerified bool
+	// Loading is set while the details are being fetched
+	Loading bool
+}
+
 func NewDetails() *DetailsModel {
 	st := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
@@ -52,11 +131,19 @@ func NewDetails() *DetailsModel {
 	vp := viewport.New(0, 0)
 	vp.MouseWheelEnabled = true
 	// viewport is unstyled; outer style draws the border
-	return &DetailsModel{style: st, vp: vp, dirty: true}
+	return &DetailsModel{style: st, vp: vp, dirty: true, linkSel: -1}
 }
 
 func (d *DetailsModel) Init() tea.Cmd { return nil }
 
+// ApplyTheme re-renders the sidebar with the active theme. The border color
+// follows on the next SetFocused.
+func (d *DetailsModel) ApplyTheme() {
+	d.style = d.style.Foreground(theme.Text)
+	d.dlRendered = ""
+	d.dirty = true
+}
+
 func (d *DetailsModel) SetSize(w, h int) {
 	if w < 1 {
 		w = 1
@@ -86,12 +173,13 @@ func (d *DetailsModel) SetFocused(f bool) {
 }
 
 // SetContent updates the sidebar content.
-func (d *DetailsModel) SetContent(title, desc, homepage, repo, npmLink string) {
+func (d *DetailsModel) SetContent(title, desc, homepage, repo, npmLink, bugs string) {
 	if title != d.title {
 		d.lastTitle = d.title
 		d.title = title
 		// selection changed -> reset scroll to top next render
 		d.resetTop = true
+		d.linkSel = -1
 	} else {
 		d.title = title
 	}
@@ -99,9 +187,70 @@ func (d *DetailsModel) SetContent(title, desc, homepage, repo, npmLink string) {
 	d.homepage = homepage
 	d.repository = repo
 	d.npmLink = npmLink
+	d.bugs = bugs
+	d.dirty = true
+}
+
+// PackageLink is a link row of the sidebar.
+type PackageLink struct {
+	// Kind is "repo", "home", "npm" or "bugs"
+	Kind string
+	URL  string
+}
+
+// PackageLinks returns the browser URLs of a package's links in sidebar
+// order, skipping empty ones.
+func PackageLinks(repo, homepage, npmLink, bugs string) []PackageLink {
+	var links []PackageLink
+	add := func(kind, u string) {
+		if u != "" {
+			links = append(links, PackageLink{Kind: kind, URL: u})
+		}
+	}
+	// Only normalize repository links; keep homepage/npm as-is
+	add("repo", ensureScheme(normalizeURL(repo)))
+	add("home", ensureScheme(homepage))
+	add("npm", ensureScheme(npmLink))
+	add("bugs", ensureScheme(bugs))
+	return links
+}
+
+// CycleLink moves the link row selection by delta, wrapping around, so the
+// selected link can be opened from the keyboard.
+func (d *DetailsModel) CycleLink(delta int) {
+	n := len(PackageLinks(d.repository, d.homepage, d.npmLink, d.bugs))
+	if n == 0 {
+		return
+	}
+	switch {
+	case d.linkSel < 0 && delta < 0:
+		d.linkSel = n - 1
+	case d.linkSel < 0:
+		d.linkSel = 0
+	default:
+		d.linkSel = (d.linkSel + delta + n) % n
+	}
+	d.linkScroll = true
 	d.dirty = true
 }
 
+// SelectedLink returns the URL of the selected link row, if any.
+func (d *DetailsModel) SelectedLink() string {
+	links := PackageLinks(d.repository, d.homepage, d.npmLink, d.bugs)
+	if d.linkSel < 0 || d.linkSel >= len(links) {
+		return ""
+	}
+	return links[d.linkSel].URL
+}
+
+// ClearLinkSelection deselects the link row.
+func (d *DetailsModel) ClearLinkSelection() {
+	if d.linkSel >= 0 {
+		d.linkSel = -1
+		d.dirty = true
+	}
+}
+
 // SetStats sets the one-line stats string (version/downloads/license/author)
 func (d *DetailsModel) SetStats(s string) { d.stats = s; d.dirty = true }
 
@@ -119,6 +268,36 @@ func (d *DetailsModel) SetDownloadsPoints(times []time.Time) {
 	d.dirty = true
 }
 
+// SetTrend sets the trend stats shown under the chart; nil hides them.
+func (d *DetailsModel) SetTrend(t *TrendStats) { d.trend = t; d.dirty = true }
+
+// SetDeprecation sets the deprecation messages of the latest version and,
+// when different, of the installed version. Empty messages hide the warning.
+func (d *DetailsModel) SetDeprecation(latestMsg, installedVersion, installedMsg string) {
+	d.deprecated = latestMsg
+	d.installedVersion = installedVersion
+	d.installedDeprecated = installedMsg
+	d.dirty = true
+}
+
+// SetFootprint sets the size/footprint section; nil hides it.
+func (d *DetailsModel) SetFootprint(f *Footprint) { d.footprint = f; d.dirty = true }
+
+// SetRisk sets the supply-chain risk signals; nil hides the section.
+func (d *DetailsModel) SetRisk(r *RiskSignals) { d.risk = r; d.dirty = true }
+
+// SetPublishing sets the maintainer and publish details; nil hides the section.
+func (d *DetailsModel) SetPublishing(p *Publishing) { d.publishing = p; d.dirty = true }
+
+// SetLikelyIntended sets the typosquat hint shown under Install risk; empty hides it.
+func (d *DetailsModel) SetLikelyIntended(s string) { d.likelyIntended = s; d.dirty = true }
+
+// SetLicenseIssue sets the license policy violation shown under Install risk; empty hides it.
+func (d *DetailsModel) SetLicenseIssue(s string) { d.licenseIssue = s; d.dirty = true }
+
+// SetChartLabel sets the caption shown above the downloads chart.
+func (d *DetailsModel) SetChartLabel(s string) { d.chartLabel = s; d.dirty = true }
+
 func (d *DetailsModel) Update(msg tea.Msg) tea.Cmd {
 	switch t := msg.(type) {
 	case tea.MouseMsg:
@@ -178,6 +357,23 @@ func (d *DetailsModel) View() string {
 		b.WriteString(wrap.Render(titleStyle.Render(d.title)))
 		b.WriteString("\n\n")
 	}
+	if d.deprecated != "" || d.installedDeprecated != "" {
+		warnHeading := lipgloss.NewStyle().Foreground(theme.Crust).Background(theme.Red).Bold(true).Padding(0, 1)
+		warnText := lipgloss.NewStyle().Foreground(theme.Red)
+		if d.deprecated != "" {
+			b.WriteString(wrap.Render(warnHeading.Render("Deprecated")))
+			b.WriteString("\n")
+			b.WriteString(wrap.Render(warnText.Render(d.deprecated)))
+			b.WriteString("\n\n")
+		}
+		// Only repeat the installed message when it adds information
+		if d.installedDeprecated != "" && d.installedDeprecated != d.deprecated {
+			b.WriteString(wrap.Render(warnHeading.Render("Installed " + d.installedVersion + " is deprecated")))
+			b.WriteString("\n")
+			b.WriteString(wrap.Render(warnText.Render(d.installedDeprecated)))
+			b.WriteString("\n\n")
+		}
+	}
 	if d.stats != "" {
 		b.WriteString(wrap.Render(d.stats))
 		// If we have downloads series, render chart with extra spacing below the info
@@ -192,19 +388,7 @@ func (d *DetailsModel) View() string {
 				}
 				h := 6
 				// Use a compact Y label formatter (e.g., 1.2k, 3.4M)
-				yFmt := func(i int, v float64) string {
-					av := math.Abs(v)
-					switch {
-					case av >= 1_000_000_000:
-						return fmt.Sprintf("%.1fB", v/1_000_000_000)
-					case av >= 1_000_000:
-						return fmt.Sprintf("%.1fM", v/1_000_000)
-					case av >= 1_000:
-						return fmt.Sprintf("%.0fk", v/1_000)
-					default:
-						return fmt.Sprintf("%.0f", v)
-					}
-				}
+				yFmt := func(i int, v float64) string { return compactNumber(v) }
 				var lastYear string
 				xFmt := func(i int, v float64) string {
 					// Reset yearly state at the start of a draw pass
@@ -285,8 +469,16 @@ func (d *DetailsModel) View() string {
 				d.dlRendered = lc.View()
 			}
 			b.WriteString("\n")
+			if d.chartLabel != "" {
+				b.WriteString(mutedStyle.Render(d.chartLabel + "  (w/g to change)"))
+				b.WriteString("\n")
+			}
 			b.WriteString(d.dlRendered)
 		}
+		if d.trend != nil {
+			b.WriteString("\n\n")
+			b.WriteString(wrap.Render(renderTrend(*d.trend)))
+		}
 		// One extra blank line under the graph for padding
 		b.WriteString("\n\n")
 		b.WriteString(sep)
@@ -303,13 +495,42 @@ func (d *DetailsModel) View() string {
 		b.WriteString(wrap.Render(styledDesc))
 		b.WriteString("\n\n")
 	}
+	if d.footprint != nil {
+		b.WriteString(wrap.Render(headingStyle.Render("Size")))
+		b.WriteString("\n\n")
+		b.WriteString(wrap.Render(renderFootprint(*d.footprint)))
+		b.WriteString("\n\n")
+	}
+	if d.publishing != nil {
+		b.WriteString(wrap.Render(headingStyle.Render("Publishing")))
+		b.WriteString("\n\n")
+		b.WriteString(wrap.Render(renderPublishing(*d.publishing)))
+		b.WriteString("\n\n")
+	}
+	var notes []string
+	for _, n := range []string{d.licenseIssue, d.likelyIntended} {
+		if n != "" {
+			notes = append(notes, lipgloss.NewStyle().Foreground(theme.Red).Render("⚠ "+n))
+		}
+	}
+	if d.risk != nil || len(notes) > 0 {
+		riskHeading := headingStyle
+		if len(notes) > 0 || d.risk.risky() {
+			riskHeading = riskHeading.Background(theme.Red)
+		}
+		b.WriteString(wrap.Render(riskHeading.Render("Install risk")))
+		b.WriteString("\n\n")
+		if d.risk != nil {
+			notes = append(notes, renderRisk(*d.risk))
+		}
+		b.WriteString(wrap.Render(strings.Join(notes, "\n")))
+		b.WriteString("\n\n")
+	}
 	// Links section with truncation and aligned icons only (no text labels)
 	labelW := 8 // space for [home] + space
 	linkW := intMax(8, innerW-labelW)
-	row := func(icon, url string) {
-		if url == "" {
-			return
-		}
+	selLine := -1
+	row := func(icon, url string, selected bool) {
 		// icon + single trailing space (no left/half padding), clickable
 		widthStyle := lipgloss.NewStyle().Width(labelW)
 		cell := widthStyle.Render(icon + " ")
@@ -317,22 +538,27 @@ func (d *DetailsModel) View() string {
 		// display text without scheme while keeping actual URL intact
 		disp := shortenLinkDisplay(url, linkW)
 		val := osc8(url, linkStyle.Render(disp))
+		if selected {
+			// the keyboard-selected row, opened with Enter
+			selLine = strings.Count(b.String(), "\n")
+			sel := lipgloss.NewStyle().Foreground(theme.Base).Background(theme.Mauve)
+			val = osc8(url, sel.Render(disp))
+		}
 		b.WriteString(lbl)
 		b.WriteString(val)
 		b.WriteString("\n")
 	}
-	// Only normalize repository links; keep homepage/npm as-is
-	repoURL := ensureScheme(normalizeURL(d.repository))
-	homeURL := ensureScheme(d.homepage)
-	npmURL := ensureScheme(d.npmLink)
 	// Icons without backgrounds; try alternative glyphs that appear larger
 	// repo: GitHub logo (Font Awesome) in white
 	// Fancy ASCII word-icons
-	repoIcon := lipgloss.NewStyle().Foreground(lipgloss.Color("#ffffff")).Render("[repo]")
-	homeIcon := lipgloss.NewStyle().Foreground(theme.Blue).Render("[home]")
-	npmIcon := lipgloss.NewStyle().Foreground(theme.Red).Render("[npm]")
-	hasLinks := repoURL != "" || homeURL != "" || npmURL != ""
-	if hasLinks {
+	icons := map[string]string{
+		"repo": lipgloss.NewStyle().Foreground(lipgloss.Color("#ffffff")).Render("[repo]"),
+		"home": lipgloss.NewStyle().Foreground(theme.Blue).Render("[home]"),
+		"npm":  lipgloss.NewStyle().Foreground(theme.Red).Render("[npm]"),
+		"bugs": lipgloss.NewStyle().Foreground(theme.Peach).Render("[bugs]"),
+	}
+	links := PackageLinks(d.repository, d.homepage, d.npmLink, d.bugs)
+	if len(links) > 0 {
 		if b.Len() > 0 {
 			b.WriteString(sep)
 			b.WriteString("\n")
@@ -341,9 +567,9 @@ func (d *DetailsModel) View() string {
 		linksLabel := headingStyle.Render("Links")
 		b.WriteString(wrap.Render(linksLabel))
 		b.WriteString("\n\n")
-		row(repoIcon, repoURL)
-		row(homeIcon, homeURL)
-		row(npmIcon, npmURL)
+		for i, l := range links {
+			row(icons[l.Kind], l.URL, i == d.linkSel)
+		}
 	}
 	// Update viewport content only when changed to preserve scroll offset
 	newContent := b.String()
@@ -355,12 +581,81 @@ func (d *DetailsModel) View() string {
 			d.vp.GotoTop()
 			d.resetTop = false
 		}
+		// Keep the selected link row in view
+		if d.linkScroll && selLine >= 0 {
+			if selLine < d.vp.YOffset || selLine >= d.vp.YOffset+d.vp.Height {
+				d.vp.SetYOffset(selLine - d.vp.Height/2)
+			}
+			d.linkScroll = false
+		}
 	}
 	// Render the viewport inside the bordered container
 	body := lipgloss.Place(innerW, innerH, lipgloss.Left, lipgloss.Top, d.vp.View())
 	return d.style.Width(innerW).Height(innerH).Render(body)
 }
 
+// renderTrend formats trend stats as a compact block of labelled lines.
+func renderTrend(t TrendStats) string {
+	label := lipgloss.NewStyle().Foreground(theme.Subtext0).Bold(true)
+	pct := func(ok bool, v float64) string {
+		if !ok {
+			return lipgloss.NewStyle().Foreground(theme.Surface2).Render("n/a")
+		}
+		st := lipgloss.NewStyle().Foreground(theme.Green)
+		if v < 0 {
+			st = st.Foreground(theme.Red)
+		}
+		return st.Render(fmt.Sprintf("%+.1f%%", v))
+	}
+	var lines []string
+	if t.Direction != "" {
+		arrow, color := "→", theme.Subtext0
+		switch t.Direction {
+		case "rising":
+			arrow, color = "↑", theme.Green
+		case "declining":
+			arrow, color = "↓", theme.Red
+		}
+		lines = append(lines, label.Render("Trend ")+lipgloss.NewStyle().Foreground(color).Bold(true).Render(arrow+" "+t.Direction))
+	}
+	lines = append(lines,
+		label.Render("WoW ")+pct(t.HasWeekOverWeek, t.WeekOverWeek)+"  "+label.Render("YoY ")+pct(t.HasYearOverYear, t.YearOverYear),
+		label.Render("4-wk avg ")+compactNumber(t.MovingAverage),
+	)
+	if !t.PeakWeek.IsZero() {
+		lines = append(lines, label.Render("Peak ")+compactNumber(t.PeakValue)+" (week of "+t.PeakWeek.Format("2006-01-02")+")")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderFootprint formats unpacked size, file count and install estimate.
+func renderFootprint(f Footprint) string {
+	label := lipgloss.NewStyle().Foreground(theme.Subtext0).Bold(true)
+	muted := lipgloss.NewStyle().Foreground(theme.Surface2)
+	unpacked := "n/a"
+	if f.UnpackedSize > 0 {
+		unpacked = formatBytes(f.UnpackedSize)
+		if f.FileCount > 0 {
+			unpacked += muted.Render(fmt.Sprintf(" (%d files)", f.FileCount))
+		}
+	}
+	lines := []string{
+		label.Render("Unpacked ") + unpacked,
+		label.Render("Direct deps ") + fmt.Sprintf("%d", f.Dependencies),
+	}
+	switch {
+	case f.Loading:
+		lines = append(lines, label.Render("Install size ")+muted.Render("calculating…"))
+	case f.TotalPackages > 0:
+		est := "~" + formatBytes(f.TotalSize) + muted.Render(fmt.Sprintf(" (%d packages)", f.TotalPackages))
+		if f.Partial {
+			est += muted.Render(" partial")
+		}
+		lines = append(lines, label.Render("Install size ")+est)
+	}
+	return strings.Join(lines, "\n")
+}
+
 // styleDescription applies lightweight inline styling to description text:
 // - `code` spans get a subtle background
 // - http(s) URLs become clickable and blue
@@ -479,3 +774,100 @@ func normalizeURL(s string) string {
 	s = strings.TrimSuffix(s, ".git")
 	return s
 }
+
+// risky mirrors commands.RiskMsg.Risky for the sidebar heading color.
+func (r RiskSignals) risky() bool {
+	return !r.Loading && (len(r.InstallScripts) > 0 || r.NewPublisher || r.Recent)
+}
+
+// renderRisk formats install scripts, publisher, publish age and whether a
+// provenance attestation exists, with a mark per signal.
+func renderRisk(r RiskSignals) string {
+	muted := lipgloss.NewStyle().Foreground(theme.Subtext0)
+	if r.Loading {
+		return muted.Render("Checking install scripts and publisher…")
+	}
+	warn := lipgloss.NewStyle().Foreground(theme.Red).Render("⚠ ")
+	ok := lipgloss.NewStyle().Foreground(theme.Green).Render("✓ ")
+	info := muted.Render("· ")
+	code := lipgloss.NewStyle().Foreground(theme.Text).Background(theme.Surface1)
+	var lines []string
+	if len(r.InstallScripts) > 0 {
+		lines = append(lines, warn+"Runs install scripts:")
+		for _, s := range r.InstallScripts {
+			name, cmd, _ := strings.Cut(s, ": ")
+			lines = append(lines, "  "+muted.Render(name+": ")+code.Render(cmd))
+		}
+	} else {
+		lines = append(lines, ok+"No install scripts")
+	}
+	if r.NewPublisher {
+		lines = append(lines, warn+"Published by "+r.Publisher+" for the first time")
+	} else if r.Publisher != "" {
+		lines = append(lines, ok+"Known publisher")
+	}
+	if r.Recent {
+		age := time.Since(r.PublishedAt)
+		lines = append(lines, warn+fmt.Sprintf("%s published %dh ago (under 72h)", r.Version, int(age.Hours())))
+	}
+	// The attestation is only decoded, not verified against Sigstore
+	if r.Provenance {
+		lines = append(lines, info+"Provenance (unverified)")
+	} else {
+		lines = append(lines, info+muted.Render("No provenance attestation"))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderPublishing formats maintainers, the last publish, provenance with a
+// source commit link, and registry signature status.
+func renderPublishing(p Publishing) string {
+	label := lipgloss.NewStyle().Foreground(theme.Subtext0).Bold(true)
+	muted := lipgloss.NewStyle().Foreground(theme.Surface2)
+	if p.Loading {
+		return muted.Render("Loading maintainers and provenance…")
+	}
+	ok := lipgloss.NewStyle().Foreground(theme.Green).Render("✓ ")
+	warn := lipgloss.NewStyle().Foreground(theme.Red).Render("⚠ ")
+	info := muted.Render("· ")
+	link := lipgloss.NewStyle().Foreground(theme.Blue)
+	var lines []string
+	if n := len(p.Maintainers); n > 0 {
+		shown := p.Maintainers
+		more := ""
+		if n > 6 {
+			shown, more = shown[:6], muted.Render(fmt.Sprintf(" +%d", n-6))
+		}
+		lines = append(lines, label.Render("Maintainers ")+strings.Join(shown, ", ")+more)
+	}
+	if p.Publisher != "" {
+		lines = append(lines, label.Render("Publisher ")+p.Publisher)
+	}
+	if !p.PublishedAt.IsZero() {
+		lines = append(lines, label.Render("Published ")+p.Version+muted.Render(" on "+p.PublishedAt.Format("2006-01-02")))
+	}
+	if p.Provenance {
+		line := info + "Provenance (unverified)"
+		if p.Builder != "" {
+			line += muted.Render(" via " + p.Builder)
+		}
+		lines = append(lines, line)
+		if p.SourceCommit != "" {
+			commit := p.SourceCommit
+			if len(commit) > 7 {
+				commit = commit[:7]
+			}
+			src := strings.TrimPrefix(p.SourceRepo, "https://")
+			lines = append(lines, "  "+label.Render("Source ")+osc8(p.CommitURL, link.Render(src+"@"+commit)))
+		}
+	}
+	switch {
+	case p.SignatureVerified:
+		lines = append(lines, ok+"Registry signature verified")
+	case p.Signed:
+		lines = append(lines, warn+"Registry signature could not be verified")
+	default:
+		lines = append(lines, info+muted.Render("No registry signature"))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 
 	"github.com/fredrikmwold/npm-tui/internal/ui/theme"
 )
@@ -17,6 +18,21 @@ type Input struct {
 	style  lipgloss.Style
 	height int
 	focus  bool
+
+	// past searches, oldest first; histIdx is the entry shown, len(history)
+	// while editing draft
+	history []string
+	histIdx int
+	draft   string
+	// suggestions offered while typing, in order of preference
+	suggestions []InputSuggestion
+}
+
+// InputSuggestion completes the input while typing. Label names a saved
+// query; it is empty for past searches.
+type InputSuggestion struct {
+	Label string
+	Value string
 }
 
 func NewInput() *Input {
@@ -24,20 +40,31 @@ func NewInput() *Input {
 	ti.Placeholder = ""
 	ti.Prompt = ""
 	ti.Focus()
-	// Colorful cursor and placeholder
-	// Set cursor color using the new API
-	c := ti.Cursor
-	c.Style = lipgloss.NewStyle().Foreground(theme.Mauve)
-	ti.Cursor = c
-	ti.PlaceholderStyle = lipgloss.NewStyle().Foreground(theme.Surface2).Italic(true)
-	ti.TextStyle = lipgloss.NewStyle().Foreground(theme.Text)
 
 	style := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(theme.BorderFocused).
 		Padding(0, 0)
 
-	return &Input{ti: ti, style: style, height: 3, focus: true}
+	i := &Input{ti: ti, style: style, height: 3, focus: true}
+	i.ApplyTheme()
+	return i
+}
+
+// ApplyTheme restyles the cursor, text and border with the active theme.
+// Labels embed their colors, so callers set the label again afterwards.
+func (i *Input) ApplyTheme() {
+	// Colorful cursor and placeholder
+	// Set cursor color using the new API
+	c := i.ti.Cursor
+	c.Style = lipgloss.NewStyle().Foreground(theme.Mauve)
+	i.ti.Cursor = c
+	i.ti.PlaceholderStyle = lipgloss.NewStyle().Foreground(theme.Surface2).Italic(true)
+	i.ti.TextStyle = lipgloss.NewStyle().Foreground(theme.Text)
+	if i.focus {
+		i.style = i.style.BorderForeground(theme.BorderFocused)
+	} else {
+		i.style = i.style.BorderForeground(theme.BorderUnfocused)
+	}
 }
 
 func (i *Input) Init() tea.Cmd { return textinput.Blink }
@@ -57,6 +84,58 @@ func (i *Input) Height() int { return i.height }
 // Value returns the current text typed in the input.
 func (i *Input) Value() string { return i.ti.Value() }
 
+// SetValue replaces the text and moves the cursor to its end.
+func (i *Input) SetValue(v string) {
+	i.ti.SetValue(v)
+	i.ti.CursorEnd()
+}
+
+// SetHistory sets the past searches recalled with Up and Down, oldest first.
+func (i *Input) SetHistory(h []string) {
+	i.history = h
+	i.histIdx, i.draft = len(h), ""
+}
+
+// SetSuggestions sets what is suggested while typing.
+func (i *Input) SetSuggestions(s []InputSuggestion) { i.suggestions = s }
+
+// recall shows the history entry d steps from the current one, keeping the
+// text being typed to come back to.
+func (i *Input) recall(d int) {
+	n := i.histIdx + d
+	if n < 0 || n > len(i.history) {
+		return
+	}
+	if i.histIdx == len(i.history) {
+		i.draft = i.ti.Value()
+	}
+	i.histIdx = n
+	if n == len(i.history) {
+		i.SetValue(i.draft)
+	} else {
+		i.SetValue(i.history[n])
+	}
+}
+
+// suggestion returns the first suggestion completing the text, either by
+// value or by saved query name, when the cursor is at the end.
+func (i *Input) suggestion() (InputSuggestion, bool) {
+	v := strings.ToLower(i.ti.Value())
+	if strings.TrimSpace(v) == "" || i.ti.Position() < len([]rune(v)) {
+		return InputSuggestion{}, false
+	}
+	for _, s := range i.suggestions {
+		value := strings.ToLower(s.Value)
+		if value == v {
+			continue
+		}
+		if strings.HasPrefix(value, v) || (s.Label != "" && strings.HasPrefix(strings.ToLower(s.Label), v)) {
+			return s, true
+		}
+	}
+	return InputSuggestion{}, false
+}
+
 // SetLabel renders a label inside the input by using the textinput prompt.
 func (i *Input) SetLabel(text string, style lipgloss.Style) {
 	// Build a colorful prompt inside the border. Keep single color (npm red)
@@ -77,6 +156,22 @@ func (i *Input) SetLabel(text string, style lipgloss.Style) {
 }
 
 func (i *Input) Update(msg tea.Msg) tea.Cmd {
+	if km, ok := msg.(tea.KeyMsg); ok {
+		switch km.Type {
+		case tea.KeyUp:
+			i.recall(-1)
+			return nil
+		case tea.KeyDown:
+			i.recall(1)
+			return nil
+		case tea.KeyRight, tea.KeyEnd:
+			// Accept the suggestion shown at the end of the line
+			if s, ok := i.suggestion(); ok {
+				i.SetValue(s.Value)
+				return nil
+			}
+		}
+	}
 	var cmd tea.Cmd
 	i.ti, cmd = i.ti.Update(msg)
 	return cmd
@@ -87,7 +182,21 @@ func (i *Input) View() string {
 	// border and padding) equals i.width. Rounded border adds 1 col per side
 	// and we configured horizontal padding of 1 per side => subtract 2.
 	innerWidth := intMax(0, i.width-2)
-	box := i.style.Width(innerWidth).Render(i.ti.View())
+	content := i.ti.View()
+	if s, ok := i.suggestion(); ok && i.focus {
+		// Show the suggestion at the right end while it fits after the text
+		// and cursor; the text input pads its view to its full width
+		hint := "→ " + s.Value
+		if s.Label != "" {
+			hint = "→ ★ " + s.Label + ": " + s.Value
+		}
+		hint = lipgloss.NewStyle().Foreground(theme.Surface2).Italic(true).Render(truncate(hint, innerWidth/2))
+		used := ansi.StringWidth(i.ti.Prompt) + ansi.StringWidth(i.ti.Value()) + 1
+		if gap := innerWidth - used - lipgloss.Width(hint); gap > 0 {
+			content = ansi.Truncate(content, used, "") + strings.Repeat(" ", gap) + hint
+		}
+	}
+	box := i.style.Width(innerWidth).Render(content)
 	return box
 }
 
@@ -107,6 +216,7 @@ func (i *Input) Clear() {
 	i.ti.SetValue("")
 	// Move cursor to start to avoid any residual position
 	i.ti.SetCursor(0)
+	i.histIdx, i.draft = len(i.history), ""
 }
 
 //
@@ -16,6 +16,11 @@ type delegate struct {
 	installing map[string]bool
 	installed  map[string]bool
 	wanted     map[string]string // manifest (wanted) versions by name
+	marked     map[string]bool   // packages marked for comparison
+	trends     map[string]string // download trend direction by name
+	licenses   map[string]string // licenses violating the policy by name
+	starred    map[string]bool   // packages on the watchlist
+	newSince   map[string]string // watched packages with a new version, by last seen version
 	frame      string
 }
 
@@ -33,9 +38,18 @@ func (d *delegate) Render(w io.Writer, m bblist.Model, index int, listItem bblis
 	it, _ := listItem.(item)
 	prefix := ""
 	suffix := ""
+	if d.marked != nil && d.marked[it.Name()] {
+		prefix = lipgloss.NewStyle().Foreground(theme.Mauve).Bold(true).Render("◆") + " "
+	}
+	if d.starred[it.Name()] {
+		prefix += lipgloss.NewStyle().Foreground(theme.Yellow).Render("★") + " "
+	}
+	if arrow := trendArrow(d.trends[it.Name()]); arrow != "" {
+		suffix = " " + arrow
+	}
 	if d.installing != nil && d.installing[it.Name()] {
 		// show spinner after the name while installing
-		suffix = " " + d.frame
+		suffix += " " + d.frame
 	} else if d.installed != nil && d.installed[it.Name()] {
 		// If installed, check if an update is available compared to latest
 		if d.wanted != nil {
@@ -46,22 +60,50 @@ func (d *delegate) Render(w io.Writer, m bblist.Model, index int, listItem bblis
 					label = fmt.Sprintf(" Outdated %s -> %s", oldV, newV)
 				}
 				warn := lipgloss.NewStyle().Foreground(theme.Peach).Bold(true).Render(label)
-				suffix = " " + warn
+				suffix += " " + warn
 			} else {
 				installed := lipgloss.NewStyle().Foreground(theme.Green).Render("✔ Installed")
-				suffix = " " + installed
+				suffix += " " + installed
 			}
 		} else {
 			installed := lipgloss.NewStyle().Foreground(theme.Green).Render("✔ Installed")
-			suffix = " " + installed
+			suffix += " " + installed
 		}
 	}
+	// Deprecation badges: latest version first, otherwise the installed copy
+	badge := lipgloss.NewStyle().Foreground(theme.Crust).Background(theme.Red).Bold(true).Padding(0, 1)
+	if it.deprecated != "" {
+		suffix += " " + badge.Render("deprecated")
+	} else if it.installedDeprecated != "" {
+		suffix += " " + badge.Render("installed "+it.installedVersion+" deprecated")
+	}
+	if seen, ok := d.newSince[it.Name()]; ok {
+		newBadge := badge.Background(theme.Sky)
+		suffix += " " + newBadge.Render(fmt.Sprintf("new %s → %s", seen, it.latest))
+	}
+	if lic, ok := d.licenses[it.Name()]; ok {
+		licBadge := badge.Background(theme.Peach)
+		suffix += " " + licBadge.Render("⚖ "+lic)
+	}
 	// Wrap the item to override Title() with spinner prefix/suffix while preserving
 	// default height/formatting.
 	wi := wrappedItem{item: it, pre: prefix, suf: suffix}
 	d.DefaultDelegate.Render(w, m, index, wi)
 }
 
+// trendArrow renders a colored arrow for a download trend direction.
+func trendArrow(direction string) string {
+	switch direction {
+	case "rising":
+		return lipgloss.NewStyle().Foreground(theme.Green).Render("↑")
+	case "declining":
+		return lipgloss.NewStyle().Foreground(theme.Red).Render("↓")
+	case "stable":
+		return lipgloss.NewStyle().Foreground(theme.Subtext0).Render("→")
+	}
+	return ""
+}
+
 // wrappedItem decorates an item with a prefix/suffix for the Title while delegating
 // other methods to the embedded item.
 type wrappedItem struct {
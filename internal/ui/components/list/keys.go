@@ -0,0 +1,44 @@
+package list
+
+import "github.com/charmbracelet/bubbles/key"
+
+// KeyMap holds the configurable package actions. The app dispatches on these
+// bindings and the list's help footer renders them.
+type KeyMap struct {
+	Install     key.Binding
+	InstallDev  key.Binding
+	Update      key.Binding
+	Changelog   key.Binding
+	Readme      key.Binding
+	ChartWindow key.Binding
+	Granularity key.Binding
+	Tarball     key.Binding
+	Mark        key.Binding
+	Compare     key.Binding
+	Theme       key.Binding
+	Copy        key.Binding
+	Open        key.Binding
+	Star        key.Binding
+	Watchlist   key.Binding
+}
+
+// DefaultKeyMap returns the built-in bindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Install:     key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "install")),
+		InstallDev:  key.NewBinding(key.WithKeys("I"), key.WithHelp("I", "install dev")),
+		Update:      key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "Update")),
+		Changelog:   key.NewBinding(key.WithKeys("l"), key.WithHelp("l", "changelog")),
+		Readme:      key.NewBinding(key.WithKeys("r", "R"), key.WithHelp("r", "README")),
+		ChartWindow: key.NewBinding(key.WithKeys("w"), key.WithHelp("w", "chart window")),
+		Granularity: key.NewBinding(key.WithKeys("g"), key.WithHelp("g", "granularity")),
+		Tarball:     key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "files")),
+		Mark:        key.NewBinding(key.WithKeys("m"), key.WithHelp("m", "mark")),
+		Compare:     key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "compare")),
+		Theme:       key.NewBinding(key.WithKeys("T"), key.WithHelp("T", "theme")),
+		Copy:        key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "copy")),
+		Open:        key.NewBinding(key.WithKeys("o"), key.WithHelp("o", "open link")),
+		Star:        key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "star")),
+		Watchlist:   key.NewBinding(key.WithKeys("W"), key.WithHelp("W", "watchlist")),
+	}
+}
@@ -20,7 +20,12 @@ type item struct {
 	homepage string
 	repo     string
 	npmLink  string
+	bugs     string
 	latest   string
+	// deprecation messages for the latest and the installed version
+	deprecated          string
+	installedVersion    string
+	installedDeprecated string
 }
 
 func (i item) Title() string       { return i.title }
@@ -42,30 +47,25 @@ type Model struct {
 	del         *delegate
 	// when true, show the README hotkey in the help footer
 	showReadmeHotkey bool
+	// package names marked for comparison, in marking order
+	marked []string
+	// action bindings shown in the help footer
+	keys KeyMap
 	// cached title styles
 	titleStyleDefault lipgloss.Style
 	titleStylePlain   lipgloss.Style
+	plainTitle        bool
 }
 
 func New() *Model {
 	// Create model first so closures can capture a stable pointer
-	m := &Model{placeholder: "Type and press Enter to search."}
+	m := &Model{placeholder: "Type and press Enter to search.", keys: DefaultKeyMap()}
 
 	// Start empty; we'll show a centered placeholder until we have results.
 	var items []bblist.Item
 
-	// Create custom delegate (wrap default styles)
+	// Create custom delegate; its styles are set by applyStyles
 	d := newDelegate()
-	// Theme normal and selected item styles
-	d.DefaultDelegate.Styles.SelectedTitle = d.DefaultDelegate.Styles.SelectedTitle.
-		Foreground(theme.Mauve).
-		BorderForeground(theme.Mauve).
-		Bold(true)
-	d.DefaultDelegate.Styles.SelectedDesc = d.DefaultDelegate.Styles.SelectedDesc.
-		Foreground(theme.Mauve).
-		BorderForeground(theme.Mauve)
-	d.DefaultDelegate.Styles.NormalTitle = d.DefaultDelegate.Styles.NormalTitle.Foreground(theme.Text)
-	d.DefaultDelegate.Styles.NormalDesc = d.DefaultDelegate.Styles.NormalDesc.Foreground(theme.Surface2)
 
 	l := bblist.New(items, d, 0, 0)
 	l.Title = "Results"
@@ -73,12 +73,12 @@ func New() *Model {
 	l.SetShowHelp(true)
 	// Disable built-in list filtering; searching is handled by the top input
 	l.SetFilteringEnabled(false)
-	// Title styles: default with background, and plain without background
-	titleDefault := lipgloss.NewStyle().Foreground(theme.Crust).Background(theme.Lavender).Bold(true).Padding(0, 1)
-	titlePlain := lipgloss.NewStyle().Foreground(theme.Crust).Bold(true).Padding(0, 0)
-	l.Styles.Title = titleDefault
-	l.Styles.PaginationStyle = lipgloss.NewStyle().Foreground(theme.Surface2)
-	l.Styles.HelpStyle = lipgloss.NewStyle().Foreground(theme.Surface2)
+	// Page with arrows, pgup/pgdn and home/end only: the letters bubbles
+	// also binds (h/l/b/f/u/d/g/G) are action keys here
+	l.KeyMap.PrevPage = key.NewBinding(key.WithKeys("left", "pgup"), key.WithHelp("←/pgup", "prev page"))
+	l.KeyMap.NextPage = key.NewBinding(key.WithKeys("right", "pgdown"), key.WithHelp("→/pgdn", "next page"))
+	l.KeyMap.GoToStart = key.NewBinding(key.WithKeys("home"), key.WithHelp("home", "go to start"))
+	l.KeyMap.GoToEnd = key.NewBinding(key.WithKeys("end"), key.WithHelp("end", "go to end"))
 
 	// Dynamic help: show update when outdated; otherwise show install keys
 	l.AdditionalShortHelpKeys = func() []key.Binding {
@@ -87,7 +87,7 @@ func New() *Model {
 		}
 		// Show README toggle early in the list when enabled
 		if m.showReadmeHotkey {
-			keys = append(keys, key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "README")))
+			keys = append(keys, m.keys.Readme, m.keys.ChartWindow, m.keys.Granularity, m.keys.Tarball)
 		}
 		if it, ok := m.list.SelectedItem().(item); ok {
 			name := it.Name()
@@ -100,18 +100,16 @@ func New() *Model {
 			}
 			if outdated {
 				// Show generic Update label in help (no version path)
-				keys = append(keys, key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "Update")))
+				keys = append(keys, m.keys.Update, m.keys.Changelog)
 			} else {
-				keys = append(keys,
-					key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "install")),
-					key.NewBinding(key.WithKeys("I"), key.WithHelp("I", "install dev")),
-				)
+				keys = append(keys, m.keys.Install, m.keys.InstallDev)
 			}
 		} else {
-			keys = append(keys,
-				key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "install")),
-				key.NewBinding(key.WithKeys("I"), key.WithHelp("I", "install dev")),
-			)
+			keys = append(keys, m.keys.Install, m.keys.InstallDev)
+		}
+		keys = append(keys, m.keys.Mark)
+		if len(m.marked) >= 2 {
+			keys = append(keys, m.keys.Compare)
 		}
 		// Global keys
 		keys = append(keys,
@@ -120,21 +118,54 @@ func New() *Model {
 		)
 		return keys
 	}
-	l.AdditionalFullHelpKeys = l.AdditionalShortHelpKeys
-
-	style := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(theme.BorderUnfocused).
-		Foreground(theme.Text)
+	// The full help (?) also lists keys that are rarely needed
+	l.AdditionalFullHelpKeys = func() []key.Binding {
+		return append(l.AdditionalShortHelpKeys(), m.keys.Copy, m.keys.Open, m.keys.Star, m.keys.Watchlist, m.keys.Theme,
+			key.NewBinding(key.WithKeys("ctrl+p"), key.WithHelp("ctrl+p", "commands")),
+			key.NewBinding(key.WithKeys("ctrl+r"), key.WithHelp("ctrl+r", "saved queries")))
+	}
 
-	m.style = style
 	m.list = l
 	m.del = d
-	m.titleStyleDefault = titleDefault
-	m.titleStylePlain = titlePlain
+	m.applyStyles()
 	return m
 }
 
+// applyStyles (re)builds every cached style from the active theme.
+func (m *Model) applyStyles() {
+	// Theme normal and selected item styles
+	ds := bblist.NewDefaultItemStyles()
+	ds.SelectedTitle = ds.SelectedTitle.
+		Foreground(theme.Mauve).
+		BorderForeground(theme.Mauve).
+		Bold(true)
+	ds.SelectedDesc = ds.SelectedDesc.
+		Foreground(theme.Mauve).
+		BorderForeground(theme.Mauve)
+	ds.NormalTitle = ds.NormalTitle.Foreground(theme.Text)
+	ds.NormalDesc = ds.NormalDesc.Foreground(theme.Surface2)
+	m.del.DefaultDelegate.Styles = ds
+
+	// Title styles: default with background, and plain without background
+	m.titleStyleDefault = lipgloss.NewStyle().Foreground(theme.Crust).Background(theme.Lavender).Bold(true).Padding(0, 1)
+	m.titleStylePlain = lipgloss.NewStyle().Foreground(theme.Crust).Bold(true).Padding(0, 0)
+	if m.plainTitle {
+		m.list.Styles.Title = m.titleStylePlain
+	} else {
+		m.list.Styles.Title = m.titleStyleDefault
+	}
+	m.list.Styles.PaginationStyle = lipgloss.NewStyle().Foreground(theme.Surface2)
+	m.list.Styles.HelpStyle = lipgloss.NewStyle().Foreground(theme.Surface2).MaxWidth(max(0, m.width-2))
+
+	m.style = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		Foreground(theme.Text)
+	m.SetFocused(m.focus)
+}
+
+// ApplyTheme restyles the list after the active theme changed.
+func (m *Model) ApplyTheme() { m.applyStyles() }
+
 // SetSize sets the outer container size; the inner list is sized to fill it
 // while accounting for borders.
 func (m *Model) SetSize(w, h int) {
@@ -223,10 +254,16 @@ func (m *Model) View() string {
 }
 
 // UsePlainTitleStyle switches the list title to a style without background.
-func (m *Model) UsePlainTitleStyle() { m.list.Styles.Title = m.titleStylePlain }
+func (m *Model) UsePlainTitleStyle() {
+	m.plainTitle = true
+	m.list.Styles.Title = m.titleStylePlain
+}
 
 // UseDefaultTitleStyle restores the default title style with background.
-func (m *Model) UseDefaultTitleStyle() { m.list.Styles.Title = m.titleStyleDefault }
+func (m *Model) UseDefaultTitleStyle() {
+	m.plainTitle = false
+	m.list.Styles.Title = m.titleStyleDefault
+}
 
 // RenderPrefixedTitle returns a title where the prefix is rendered with the
 // default background style while the suffix remains unstyled (e.g., spinner).
@@ -261,6 +298,9 @@ func (m *Model) RenderPrefixedTitlePlain(prefix, suffix string) string {
 	return m.titleStylePlain.Render(prefix) + suffix
 }
 
+// SetKeyMap replaces the action bindings shown in the help footer.
+func (m *Model) SetKeyMap(k KeyMap) { m.keys = k }
+
 // SetShowReadmeHotkey toggles the presence of the README hotkey in the footer help.
 func (m *Model) SetShowReadmeHotkey(show bool) { m.showReadmeHotkey = show }
 
@@ -279,6 +319,17 @@ func countLines(s string) int {
 // IsEmpty reports whether the list has any items.
 func (m *Model) IsEmpty() bool { return len(m.list.Items()) == 0 }
 
+// LatestVersions returns the latest version of each listed package by name.
+func (m *Model) LatestVersions() map[string]string {
+	out := make(map[string]string, len(m.list.Items()))
+	for _, li := range m.list.Items() {
+		if it, ok := li.(item); ok {
+			out[it.Name()] = it.latest
+		}
+	}
+	return out
+}
+
 // SetPlaceholder updates the empty-state text.
 func (m *Model) SetPlaceholder(s string) { m.placeholder = s }
 
@@ -298,6 +349,14 @@ type Details struct {
 	Homepage    string
 	Repository  string
 	NPMLink     string
+	// Bugs is the issue tracker URL from package.json
+	Bugs   string
+	Latest string
+	// Deprecated is the deprecation message of the latest version
+	Deprecated string
+	// InstalledVersion and InstalledDeprecated describe the copy in node_modules
+	InstalledVersion    string
+	InstalledDeprecated string
 }
 
 // SelectedDetails returns sidebar-ready metadata for the currently selected item.
@@ -310,6 +369,12 @@ func (m *Model) SelectedDetails() (Details, bool) {
 			Homepage:    it.homepage,
 			Repository:  it.repo,
 			NPMLink:     it.npmLink,
+			Bugs:        it.bugs,
+			Latest:      it.latest,
+
+			Deprecated:          it.deprecated,
+			InstalledVersion:    it.installedVersion,
+			InstalledDeprecated: it.installedDeprecated,
 		}, true
 	}
 	return Details{}, false
@@ -335,7 +400,12 @@ type ItemWithMeta struct {
 	Homepage   string
 	Repository string
 	NPMLink    string
+	Bugs       string
 	Latest     string
+
+	Deprecated          string
+	InstalledVersion    string
+	InstalledDeprecated string
 }
 
 // SetItemsWithMeta replaces items and attaches metadata for the sidebar.
@@ -349,7 +419,12 @@ func (m *Model) SetItemsWithMeta(title string, items []ItemWithMeta) {
 			homepage:    it.Homepage,
 			repo:        it.Repository,
 			npmLink:     it.NPMLink,
+			bugs:        it.Bugs,
 			latest:      it.Latest,
+
+			deprecated:          it.Deprecated,
+			installedVersion:    it.InstalledVersion,
+			installedDeprecated: it.InstalledDeprecated,
 		})
 	}
 	m.list.SetItems(itms)
@@ -380,6 +455,38 @@ func (m *Model) SetInstalled(installed map[string]bool) {
 	}
 }
 
+// SetTrends updates download trend directions ("rising", "stable",
+// "declining") shown as arrows after package names.
+func (m *Model) SetTrends(trends map[string]string) {
+	if m.del != nil {
+		m.del.trends = trends
+	}
+}
+
+// SetLicenseViolations updates the licenses, by package name, that violate the
+// license policy; those rows get a warning badge.
+func (m *Model) SetLicenseViolations(licenses map[string]string) {
+	if m.del != nil {
+		m.del.licenses = licenses
+	}
+}
+
+// SetStarred updates the package names on the watchlist; those rows get a
+// star.
+func (m *Model) SetStarred(starred map[string]bool) {
+	if m.del != nil {
+		m.del.starred = starred
+	}
+}
+
+// SetNewSince updates the watched packages with a version newer than the one
+// last seen, by that version; those rows get a badge.
+func (m *Model) SetNewSince(seen map[string]string) {
+	if m.del != nil {
+		m.del.newSince = seen
+	}
+}
+
 // SetWantedVersions updates manifest version specs used to compute updates.
 func (m *Model) SetWantedVersions(wanted map[string]string) {
 	if m.del != nil {
@@ -387,6 +494,49 @@ func (m *Model) SetWantedVersions(wanted map[string]string) {
 	}
 }
 
+// ToggleMarked marks or unmarks name for comparison. It refuses to mark more
+// than limit packages, reporting full, and reports whether name is marked
+// afterwards.
+func (m *Model) ToggleMarked(name string, limit int) (marked, full bool) {
+	for i, n := range m.marked {
+		if n == name {
+			m.marked = append(m.marked[:i], m.marked[i+1:]...)
+			m.syncMarked()
+			return false, false
+		}
+	}
+	if len(m.marked) >= limit {
+		return false, true
+	}
+	m.marked = append(m.marked, name)
+	m.syncMarked()
+	return true, false
+}
+
+// Marked returns the names marked for comparison in marking order.
+func (m *Model) Marked() []string {
+	out := make([]string, len(m.marked))
+	copy(out, m.marked)
+	return out
+}
+
+// ClearMarked removes all comparison marks.
+func (m *Model) ClearMarked() {
+	m.marked = nil
+	m.syncMarked()
+}
+
+func (m *Model) syncMarked() {
+	if m.del == nil {
+		return
+	}
+	set := make(map[string]bool, len(m.marked))
+	for _, n := range m.marked {
+		set[n] = true
+	}
+	m.del.marked = set
+}
+
 // max helper (local copy)
 func max(a, b int) int {
 	if a > b {
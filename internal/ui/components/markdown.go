@@ -21,6 +21,12 @@ type MarkdownViewer struct {
 	// cached renderer for current wrap width
 	renderer *glamour.TermRenderer
 	rwidth   int
+	// search, outline and link navigation (see readmenav.go)
+	nav docNav
+	// base URLs for relative links/images and the labels to hyperlink after
+	// rendering (see mdrewrite.go)
+	base docBase
+	refs []docRef
 }
 
 func NewMarkdownViewer() *MarkdownViewer {
@@ -28,11 +34,11 @@ func NewMarkdownViewer() *MarkdownViewer {
 	vp.MouseWheelEnabled = true
 	// Styled similarly to other panes for consistency
 	vp.Style = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(theme.BorderFocused).Foreground(theme.Text)
-	mv := &MarkdownViewer{vp: vp}
+	mv := &MarkdownViewer{vp: vp, nav: newDocNav()}
 
 	// Pre-warm a renderer to avoid a noticeable pause on the first render.
 	// Use a sensible default wrap width; render() will recreate if needed.
-	if r, err := glamour.NewTermRenderer(glamour.WithAutoStyle(), glamour.WithWordWrap(80)); err == nil {
+	if r, err := glamour.NewTermRenderer(glamour.WithStyles(theme.GlamourStyle()), glamour.WithWordWrap(80)); err == nil {
 		mv.renderer = r
 		mv.rwidth = 80
 		// Do a tiny render to warm internal caches so the first real render
@@ -44,6 +50,20 @@ func NewMarkdownViewer() *MarkdownViewer {
 
 func (m *MarkdownViewer) Init() tea.Cmd { return nil }
 
+// ApplyTheme restyles the frame and search input and re-renders the current
+// document with a glamour style derived from the active theme.
+func (m *MarkdownViewer) ApplyTheme() {
+	m.vp.Style = m.vp.Style.BorderForeground(theme.BorderFocused).Foreground(theme.Text)
+	m.nav.applyTheme()
+	// The cached renderer holds the old style
+	m.renderer = nil
+	if m.md != "" {
+		off := m.vp.YOffset
+		m.render()
+		m.vp.SetYOffset(off)
+	}
+}
+
 func (m *MarkdownViewer) SetSize(w, h int) {
 	if w < 1 {
 		w = 1
@@ -56,40 +76,75 @@ func (m *MarkdownViewer) SetSize(w, h int) {
 	fw, fh := m.vp.Style.GetFrameSize()
 	// Add a tiny fudge to compensate for terminal/font rounding issues
 	innerW := intMax(0, w-fw+2)
-	innerH := intMax(0, h-fh+2)
+	// Reserve one line below the document for the navigation footer
+	innerH := intMax(0, h-fh+1)
 	// Set viewport content area to inner dimensions
 	m.vp.Width = innerW
 	m.vp.Height = innerH
 	// Set style to the outer allocated size to guarantee fill
-	m.vp.Style = m.vp.Style.Width(w).Height(h)
+	m.vp.Style = m.vp.Style.Width(w).Height(intMax(0, h-1))
 	// Re-render with new wrap width
 	if m.md != "" && m.html != "" {
 		// re-wrap existing rendered content by re-rendering
 		m.render()
 	} else if m.html != "" {
 		// just update viewport size on plain content
-		m.vp.SetContent(m.html)
+		m.applyHighlights()
 	}
 	// No dynamic height calibration here; rely on computed inner height
 }
 
+// SetBase sets the repository URLs that relative links (blobRoot) and images
+// (rawRoot) in the next document resolve against; dir is the document's
+// directory inside the repository. Empty roots leave relative URLs untouched.
+func (m *MarkdownViewer) SetBase(blobRoot, rawRoot, dir string) {
+	m.base = docBase{blobRoot: blobRoot, rawRoot: rawRoot, dir: dir}
+}
+
 // SetMarkdown sets the markdown content and renders it.
 func (m *MarkdownViewer) SetMarkdown(md string) {
+	md, m.refs = rewriteMarkdown(md, m.base)
 	m.md = md
+	m.resetNav()
+	m.nav.parseStructure(md)
 	// Show plain markdown immediately to avoid perceived lag; glamour async may update later
 	m.vp.SetContent(md)
 	m.vp.GotoTop()
 	m.render()
 }
 
+// SetDocument shows content according to the format implied by filename:
+// Markdown is rendered with glamour, reStructuredText is converted to
+// Markdown first, and plain text is shown preformatted.
+func (m *MarkdownViewer) SetDocument(content, filename string) {
+	switch DetectFormat(filename) {
+	case FormatRST:
+		m.SetMarkdown(RSTToMarkdown(content))
+	case FormatPlain:
+		m.SetPlain(strings.ReplaceAll(content, "\t", "    "))
+	default:
+		m.SetMarkdown(content)
+	}
+}
+
 // SetPlain sets a plain, already-rendered message without glamour. Instant.
 func (m *MarkdownViewer) SetPlain(s string) {
 	m.md = ""
+	m.refs = nil
 	m.html = s
-	m.vp.SetContent(s)
+	m.resetNav()
+	m.nav.parseStructure("")
+	m.refreshNav()
 	m.vp.GotoTop()
 }
 
+// resetNav clears search and outline state when a new document is shown.
+func (m *MarkdownViewer) resetNav() {
+	m.nav.searching, m.nav.outlineOpen = false, false
+	m.nav.input.Blur()
+	m.nav.query, m.nav.matches = "", nil
+}
+
 // SetLoading sets a centered loading message with a spinner.
 func (m *MarkdownViewer) SetLoading(label, spin string) {
 	// Compute inner area (viewport content area)
@@ -131,7 +186,7 @@ func (m *MarkdownViewer) render() {
 	var err error
 	if m.renderer == nil {
 		m.renderer, err = glamour.NewTermRenderer(
-			glamour.WithAutoStyle(),
+			glamour.WithStyles(theme.GlamourStyle()),
 			glamour.WithWordWrap(w),
 		)
 		m.rwidth = w
@@ -145,10 +200,10 @@ func (m *MarkdownViewer) render() {
 			m.html = m.md
 		} else {
 			// Ensure no leading newline that adds extra spacing at top
-			m.html = strings.TrimLeft(out, "\n")
+			m.html = linkifyRendered(strings.TrimLeft(out, "\n"), m.refs)
 		}
 	}
-	m.vp.SetContent(m.html)
+	m.refreshNav()
 	// Do not recalibrate height here; stick to SetSize-provided height
 }
 
@@ -179,7 +234,7 @@ func (m *MarkdownViewer) RenderAsync(md string, seq int) tea.Cmd {
 			// Create a local renderer if none cached for this width
 			var err error
 			r, err = glamour.NewTermRenderer(
-				glamour.WithAutoStyle(),
+				glamour.WithStyles(theme.GlamourStyle()),
 				glamour.WithWordWrap(w),
 			)
 			if err != nil {
@@ -199,10 +254,14 @@ func (m *MarkdownViewer) Update(msg tea.Msg) tea.Cmd {
 	switch t := msg.(type) {
 	case MarkdownRenderedMsg:
 		// set rendered content
-		m.html = t.Content
-		m.vp.SetContent(m.html)
+		m.html = linkifyRendered(t.Content, m.refs)
+		m.refreshNav()
 		m.vp.GotoTop()
 		// fall through to allow viewport to process message & refresh
+	case tea.KeyMsg:
+		if m.Captures(t) {
+			return m.handleNavKey(t)
+		}
 	}
 	var cmd tea.Cmd
 	m.vp, cmd = m.vp.Update(msg)
@@ -211,7 +270,15 @@ func (m *MarkdownViewer) Update(msg tea.Msg) tea.Cmd {
 
 func (m *MarkdownViewer) View() string {
 	// Return the viewport's own view; its style/border already matches SetSize.
-	return m.vp.View()
+	body := m.vp.View()
+	if m.nav.outlineOpen {
+		// Reuse the viewport frame to show the outline in place of the document
+		ov := m.vp
+		ov.SetContent(m.outlineView(m.vp.Width-2, m.vp.Height-2))
+		ov.GotoTop()
+		body = ov.View()
+	}
+	return body + "\n" + m.navFooter(m.width)
 }
 
 // countLines returns the number of lines in a rendered string.
@@ -0,0 +1,299 @@
+package components
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// docRef is a piece of rendered text that should become a clickable OSC 8
+// hyperlink (image alt text and collapsed badges).
+type docRef struct {
+	label string
+	url   string
+}
+
+// docBase holds the URLs relative links and images are resolved against.
+type docBase struct {
+	blobRoot string // e.g. https://github.com/owner/repo/blob/HEAD/
+	rawRoot  string // e.g. https://raw.githubusercontent.com/owner/repo/HEAD/
+	dir      string // document directory inside the repository
+}
+
+var (
+	mdLinkedImage = regexp.MustCompile(`\[!\[([^\]]*)\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)`)
+	mdImage       = regexp.MustCompile(`!\[([^\]]*)\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)`)
+	mdLink        = regexp.MustCompile(`(\[[^\]]*\]\(\s*)<?([^)\s>]+)>?`)
+	mdRefDef      = regexp.MustCompile(`^(\s{0,3}\[[^\]]+\]:\s*)<?(\S+?)>?(\s.*)?$`)
+	htmlImg       = regexp.MustCompile(`(?i)<img\s[^>]*>`)
+	htmlAnchor    = regexp.MustCompile(`(?is)<a\s[^>]*href\s*=\s*["']([^"']+)["'][^>]*>\s*(.*?)\s*</a>`)
+	htmlAttr      = regexp.MustCompile(`(?i)\b(src|alt)\s*=\s*["']([^"']*)["']`)
+	// wrapper tags used to center logos and badges; CommonMark would otherwise
+	// treat the whole block as raw HTML and glamour drops it
+	htmlWrapper = regexp.MustCompile(`(?i)</?(?:p|div|picture|h[1-6]|span)(?:\s[^>]*)?>|<source\s[^>]*>|<br\s*/?>`)
+	mdImageRef  = regexp.MustCompile("\x00img(\\d+)\x00")
+)
+
+// badgeHosts are image hosts that serve README status badges.
+var badgeHosts = []string{
+	"img.shields.io", "shields.io", "badge.fury.io", "badgen.net", "travis-ci.org", "travis-ci.com",
+	"codecov.io", "coveralls.io", "circleci.com", "app.netlify.com", "packagephobia.com",
+	"deepscan.io", "snyk.io", "david-dm.org", "badges.gitter.im", "opencollective.com",
+}
+
+// isBadge reports whether an image URL looks like a status badge.
+func isBadge(src string) bool {
+	u, err := url.Parse(src)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(u.Host)
+	for _, h := range badgeHosts {
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+	p := strings.ToLower(u.Path)
+	return strings.Contains(p, "badge") || strings.HasSuffix(p, "/shield.svg")
+}
+
+// resolve makes href absolute against root/dir. Absolute URLs, anchors and
+// other schemes are returned unchanged, as is everything when no base is set.
+func (b docBase) resolve(root, href string) string {
+	if root == "" || href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(href, "//") {
+		return href
+	}
+	if u, err := url.Parse(href); err != nil || u.Scheme != "" {
+		return href
+	}
+	rest := ""
+	if i := strings.IndexAny(href, "?#"); i >= 0 {
+		href, rest = href[:i], href[i:]
+	}
+	var p string
+	if strings.HasPrefix(href, "/") {
+		p = path.Clean(href)
+	} else {
+		p = path.Clean(path.Join("/", b.dir, href))
+	}
+	return root + strings.TrimPrefix(p, "/") + rest
+}
+
+// rewriteMarkdown prepares a README for rendering: HTML images and anchors
+// become Markdown, relative links resolve against the repository's blob URL
+// and relative images against its raw URL, rows of badges collapse into one
+// summary line and remaining images are replaced by their alt text. The
+// returned refs list the labels (in document order) to make clickable.
+func rewriteMarkdown(md string, base docBase) (string, []docRef) {
+	var images []docRef
+	// placeholder returns a marker for an image that is expanded once the
+	// line is known to be a badge row or a regular line.
+	placeholder := func(alt, src, href string) string {
+		src = base.resolve(base.rawRoot, src)
+		if href != "" {
+			href = base.resolve(base.blobRoot, href)
+		}
+		target := href
+		if target == "" {
+			target = src
+		}
+		label := strings.TrimSpace(alt)
+		if label == "" {
+			label = path.Base(strings.SplitN(src, "?", 2)[0])
+		}
+		images = append(images, docRef{label: label, url: target})
+		// Badges are flagged with a \x01 prefix until rows are collapsed
+		if isBadge(src) {
+			images[len(images)-1].label = "\x01" + label
+		}
+		return "\x00img" + strconv.Itoa(len(images)-1) + "\x00"
+	}
+
+	lines := strings.Split(strings.ReplaceAll(md, "\r\n", "\n"), "\n")
+	inFence := false
+	for i, l := range lines {
+		if t := strings.TrimSpace(l); strings.HasPrefix(t, "```") || strings.HasPrefix(t, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		// HTML → Markdown
+		l = htmlImg.ReplaceAllStringFunc(l, func(tag string) string {
+			var src, alt string
+			for _, a := range htmlAttr.FindAllStringSubmatch(tag, -1) {
+				if strings.EqualFold(a[1], "src") {
+					src = a[2]
+				} else {
+					alt = a[2]
+				}
+			}
+			if src == "" {
+				return ""
+			}
+			return "![" + alt + "](" + src + ")"
+		})
+		l = htmlAnchor.ReplaceAllString(l, "[$2]($1)")
+		l = htmlWrapper.ReplaceAllString(l, "")
+		// Images, then links (so linked images are not seen as links)
+		l = mdLinkedImage.ReplaceAllStringFunc(l, func(s string) string {
+			m := mdLinkedImage.FindStringSubmatch(s)
+			return placeholder(m[1], m[2], m[3])
+		})
+		l = mdImage.ReplaceAllStringFunc(l, func(s string) string {
+			m := mdImage.FindStringSubmatch(s)
+			return placeholder(m[1], m[2], "")
+		})
+		l = mdLink.ReplaceAllStringFunc(l, func(s string) string {
+			m := mdLink.FindStringSubmatch(s)
+			return m[1] + base.resolve(base.blobRoot, m[2])
+		})
+		if m := mdRefDef.FindStringSubmatch(l); m != nil {
+			l = m[1] + base.resolve(base.blobRoot, m[2]) + m[3]
+		}
+		lines[i] = l
+	}
+
+	// Collapse runs of badge-only lines into one summary line
+	var out []string
+	var refs []docRef
+	var badges []docRef
+	flush := func() {
+		if len(badges) == 0 {
+			return
+		}
+		labels := make([]string, len(badges))
+		for i, b := range badges {
+			labels[i] = escapeMarkdown(b.label)
+		}
+		out = append(out, "Badges: "+strings.Join(labels, " · "), "")
+		refs = append(refs, badges...)
+		badges = nil
+	}
+	for _, l := range lines {
+		t := strings.TrimSpace(l)
+		if t != "" && mdImageRef.MatchString(t) {
+			only := strings.TrimSpace(mdImageRef.ReplaceAllString(t, ""))
+			ids := mdImageRef.FindAllStringSubmatch(t, -1)
+			allBadges := only == ""
+			for _, id := range ids {
+				if !strings.HasPrefix(images[mustAtoi(id[1])].label, "\x01") {
+					allBadges = false
+				}
+			}
+			if allBadges {
+				for _, id := range ids {
+					img := images[mustAtoi(id[1])]
+					badges = append(badges, docRef{label: strings.TrimPrefix(img.label, "\x01"), url: img.url})
+				}
+				continue
+			}
+		}
+		if t != "" || len(badges) == 0 {
+			flush()
+		} else {
+			// allow blank lines between rows of the same badge block
+			continue
+		}
+		l = mdImageRef.ReplaceAllStringFunc(l, func(s string) string {
+			img := images[mustAtoi(mdImageRef.FindStringSubmatch(s)[1])]
+			label := "🖼 " + strings.TrimPrefix(img.label, "\x01")
+			refs = append(refs, docRef{label: label, url: img.url})
+			return escapeMarkdown(label)
+		})
+		out = append(out, l)
+	}
+	flush()
+	return strings.Join(out, "\n"), refs
+}
+
+// mustAtoi parses a placeholder index produced by rewriteMarkdown.
+func mustAtoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// escapeMarkdown backslash-escapes characters that would change inline formatting.
+func escapeMarkdown(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune("\\`*_[]<>#|", r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// linkifyRendered wraps each ref label in the rendered output with an OSC 8
+// hyperlink. Labels are searched in order, continuing from the previous hit.
+func linkifyRendered(rendered string, refs []docRef) string {
+	if len(refs) == 0 {
+		return rendered
+	}
+	lines := strings.Split(rendered, "\n")
+	plain := strings.Split(ansi.Strip(rendered), "\n")
+	li := 0
+	for _, ref := range refs {
+		needle := []rune(ref.label)
+		for ; li < len(plain) && li < len(lines); li++ {
+			col := indexFold([]rune(plain[li]), needle, 0)
+			if col < 0 {
+				continue
+			}
+			start := ansiByteOffset(lines[li], col)
+			end := ansiByteOffset(lines[li], col+len(needle))
+			lines[li] = lines[li][:start] + osc8(ref.url, lines[li][start:end]) + lines[li][end:]
+			// blank out the hit so the next ref on this line finds its own label
+			rs := []rune(plain[li])
+			for k := col; k < col+len(needle); k++ {
+				rs[k] = '\x00'
+			}
+			plain[li] = string(rs)
+			break
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ansiByteOffset returns the byte offset in s of the col-th visible rune,
+// skipping CSI and OSC escape sequences.
+func ansiByteOffset(s string, col int) int {
+	n := 0
+	for i := 0; i < len(s); {
+		if s[i] == 0x1b && i+1 < len(s) {
+			switch s[i+1] {
+			case '[':
+				j := i + 2
+				for j < len(s) && (s[j] < 0x40 || s[j] > 0x7e) {
+					j++
+				}
+				i = j + 1
+				continue
+			case ']':
+				j := i + 2
+				for j < len(s) && s[j] != 0x07 && !(s[j] == 0x1b && j+1 < len(s) && s[j+1] == '\\') {
+					j++
+				}
+				if j < len(s) && s[j] == 0x1b {
+					j++
+				}
+				i = j + 1
+				continue
+			}
+		}
+		if n == col {
+			return i
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		n++
+	}
+	return len(s)
+}
@@ -0,0 +1,238 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/sahilm/fuzzy"
+
+	"github.com/fredrikmwold/npm-tui/internal/ui/theme"
+)
+
+// PaletteItem is an action offered by the command palette.
+type PaletteItem struct {
+	// ID is returned in PaletteRunMsg when the item is chosen
+	ID    string
+	Title string
+	// Key is the hotkey shown next to the title; empty when unbound
+	Key string
+}
+
+// PaletteRunMsg is emitted when an item is chosen in the palette.
+type PaletteRunMsg struct {
+	ID string
+}
+
+// Palette is a fuzzy-searchable list of actions shown over the app. The
+// caller opens it with the actions that apply at the moment and runs the
+// chosen one on PaletteRunMsg. It also serves as a picker, e.g. for saved
+// queries.
+type Palette struct {
+	width  int
+	height int
+
+	open    bool
+	input   textinput.Model
+	items   []PaletteItem
+	matches []fuzzy.Match
+	sel     int
+	offset  int
+}
+
+// paletteRows is the maximum number of actions shown at once.
+const paletteRows = 10
+
+func NewPalette() *Palette {
+	ti := textinput.New()
+	ti.Prompt = "> "
+	p := &Palette{input: ti}
+	p.ApplyTheme()
+	return p
+}
+
+// ApplyTheme restyles the search input with the active theme.
+func (p *Palette) ApplyTheme() {
+	p.input.PromptStyle = lipgloss.NewStyle().Foreground(theme.Mauve)
+	p.input.TextStyle = lipgloss.NewStyle().Foreground(theme.Text)
+	p.input.PlaceholderStyle = lipgloss.NewStyle().Foreground(theme.Surface2).Italic(true)
+}
+
+// SetSize sets the area the palette is centered in.
+func (p *Palette) SetSize(w, h int) {
+	p.width, p.height = w, h
+	p.input.Width = intMax(1, p.boxWidth()-4-lipgloss.Width(p.input.Prompt))
+}
+
+// Open shows the palette with items and an empty query, with placeholder
+// in the search input.
+func (p *Palette) Open(placeholder string, items []PaletteItem) tea.Cmd {
+	p.open = true
+	p.items = items
+	p.input.Placeholder = placeholder
+	p.input.SetValue("")
+	p.filter()
+	return p.input.Focus()
+}
+
+// Close hides the palette.
+func (p *Palette) Close() {
+	p.open = false
+	p.input.Blur()
+}
+
+// IsOpen reports whether the palette is showing.
+func (p *Palette) IsOpen() bool { return p.open }
+
+func (p *Palette) Update(msg tea.Msg) tea.Cmd {
+	km, ok := msg.(tea.KeyMsg)
+	if !ok {
+		var cmd tea.Cmd
+		p.input, cmd = p.input.Update(msg)
+		return cmd
+	}
+	switch km.String() {
+	case "esc", "ctrl+p", "ctrl+r":
+		p.Close()
+		return nil
+	case "enter":
+		if len(p.matches) == 0 {
+			return nil
+		}
+		id := p.items[p.matches[p.sel].Index].ID
+		p.Close()
+		return func() tea.Msg { return PaletteRunMsg{ID: id} }
+	case "up", "ctrl+k":
+		p.move(-1)
+		return nil
+	case "down", "ctrl+j", "ctrl+n", "tab":
+		p.move(1)
+		return nil
+	}
+	q := p.input.Value()
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	if p.input.Value() != q {
+		p.filter()
+	}
+	return cmd
+}
+
+// filter ranks the items against the query. An empty query keeps the
+// caller's order.
+func (p *Palette) filter() {
+	p.sel, p.offset = 0, 0
+	q := strings.TrimSpace(p.input.Value())
+	if q == "" {
+		p.matches = make([]fuzzy.Match, len(p.items))
+		for i, it := range p.items {
+			p.matches[i] = fuzzy.Match{Str: it.Title, Index: i}
+		}
+		return
+	}
+	titles := make([]string, len(p.items))
+	for i, it := range p.items {
+		titles[i] = it.Title
+	}
+	p.matches = fuzzy.Find(q, titles)
+}
+
+func (p *Palette) move(d int) {
+	if len(p.matches) == 0 {
+		return
+	}
+	p.sel = (p.sel + d + len(p.matches)) % len(p.matches)
+	if p.sel < p.offset {
+		p.offset = p.sel
+	}
+	if p.sel >= p.offset+paletteRows {
+		p.offset = p.sel - paletteRows + 1
+	}
+}
+
+func (p *Palette) boxWidth() int {
+	return intMax(20, min(64, p.width-4))
+}
+
+// View renders the palette box; Overlay centers it over the app.
+func (p *Palette) View() string {
+	w := p.boxWidth()
+	iw := w - 4 // border and padding
+	keyStyle := lipgloss.NewStyle().Foreground(theme.Subtext0)
+	textStyle := lipgloss.NewStyle().Foreground(theme.Text)
+	hitStyle := lipgloss.NewStyle().Foreground(theme.Mauve).Bold(true)
+	selStyle := lipgloss.NewStyle().Foreground(theme.Base).Background(theme.Mauve)
+	mutedStyle := lipgloss.NewStyle().Foreground(theme.Surface2)
+
+	lines := []string{p.input.View(), mutedStyle.Render(strings.Repeat("─", iw))}
+	if len(p.matches) == 0 {
+		lines = append(lines, mutedStyle.Render("No matches"))
+	}
+	end := min(len(p.matches), p.offset+paletteRows)
+	for i := p.offset; i < end; i++ {
+		mt := p.matches[i]
+		it := p.items[mt.Index]
+		// Keys may be long, like the query of a saved search
+		k := truncate(it.Key, iw/2)
+		keyW := lipgloss.Width(k)
+		title := truncate(it.Title, intMax(1, iw-keyW-1))
+		gap := strings.Repeat(" ", intMax(1, iw-lipgloss.Width(title)-keyW))
+		if i == p.sel {
+			lines = append(lines, selStyle.Render(title+gap+k))
+			continue
+		}
+		lines = append(lines, highlightMatches(title, mt.MatchedIndexes, textStyle, hitStyle)+gap+keyStyle.Render(k))
+	}
+	if len(p.matches) > paletteRows {
+		lines = append(lines, mutedStyle.Render(strings.Repeat(" ", intMax(0, iw-9))+"↓ more…"))
+	}
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.BorderFocused).
+		Padding(0, 1).
+		Width(w - 2)
+	return box.Render(strings.Join(lines, "\n"))
+}
+
+// highlightMatches renders s with the bytes at idx in hit and the rest in
+// base.
+func highlightMatches(s string, idx []int, base, hit lipgloss.Style) string {
+	if len(idx) == 0 {
+		return base.Render(s)
+	}
+	matched := make(map[int]bool, len(idx))
+	for _, i := range idx {
+		matched[i] = true
+	}
+	var b strings.Builder
+	for i, r := range s {
+		if matched[i] {
+			b.WriteString(hit.Render(string(r)))
+		} else {
+			b.WriteString(base.Render(string(r)))
+		}
+	}
+	return b.String()
+}
+
+// Overlay draws fg over bg with its top-left corner at column x and line y.
+// Both may contain ANSI styling.
+func Overlay(bg, fg string, x, y int) string {
+	bgLines := strings.Split(bg, "\n")
+	for i, line := range strings.Split(fg, "\n") {
+		row := y + i
+		if row < 0 || row >= len(bgLines) {
+			continue
+		}
+		under := bgLines[row]
+		left := ansi.Truncate(under, x, "")
+		if pad := x - ansi.StringWidth(left); pad > 0 {
+			left += strings.Repeat(" ", pad)
+		}
+		right := ansi.TruncateLeft(under, x+ansi.StringWidth(line), "")
+		bgLines[row] = left + "\x1b[0m" + line + "\x1b[0m" + right
+	}
+	return strings.Join(bgLines, "\n")
+}
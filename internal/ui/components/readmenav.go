@@ -0,0 +1,471 @@
+package components
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/text"
+
+	"github.com/fredrikmwold/npm-tui/internal/ui/theme"
+)
+
+// ReadmeLinkMsg is emitted when the user follows a link in the viewer. Href is
+// the link destination exactly as written in the document.
+type ReadmeLinkMsg struct {
+	Href string
+}
+
+// docHeading is a heading found in the Markdown AST.
+type docHeading struct {
+	level int
+	text  string
+	slug  string
+	line  int // rendered line, -1 when not located
+}
+
+// docLink is a link found in the Markdown AST.
+type docLink struct {
+	text string
+	href string
+	line int // rendered line, -1 when not located
+	col  int // rune offset of text in the rendered line
+}
+
+// docMatch is a search hit in the rendered (ANSI-stripped) lines.
+type docMatch struct {
+	line, start, end int // rune offsets
+}
+
+// docNav holds the search, outline and link navigation state of the viewer.
+type docNav struct {
+	headings []docHeading
+	links    []docLink
+	plain    []string // rendered lines without ANSI sequences
+
+	searching bool
+	input     textinput.Model
+	query     string
+	matches   []docMatch
+	cur       int
+
+	outlineOpen bool
+	outlineSel  int
+
+	linkSel int // -1 when no link is selected
+}
+
+func newDocNav() docNav {
+	ti := textinput.New()
+	ti.Prompt = "/"
+	n := docNav{input: ti, linkSel: -1}
+	n.applyTheme()
+	return n
+}
+
+// applyTheme restyles the search input with the active theme.
+func (n *docNav) applyTheme() {
+	n.input.PromptStyle = lipgloss.NewStyle().Foreground(theme.Mauve)
+	n.input.TextStyle = lipgloss.NewStyle().Foreground(theme.Text)
+}
+
+var navLinkStyle = lipgloss.NewStyle().Reverse(true).Bold(true)
+
+func navHintStyle() lipgloss.Style { return lipgloss.NewStyle().Foreground(theme.Subtext0) }
+
+func navMatchStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Background(theme.Yellow).Foreground(theme.Base)
+}
+
+func navCurStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Background(theme.Peach).Foreground(theme.Base).Bold(true)
+}
+
+// parseStructure extracts headings and links from Markdown source.
+func (n *docNav) parseStructure(md string) {
+	n.headings, n.links = nil, nil
+	n.outlineSel, n.linkSel = 0, -1
+	if md == "" {
+		return
+	}
+	src := []byte(md)
+	doc := goldmark.New(goldmark.WithExtensions(extension.GFM)).Parser().Parse(text.NewReader(src))
+	_ = ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch v := node.(type) {
+		case *ast.Heading:
+			t := strings.TrimSpace(string(v.Text(src)))
+			if t != "" {
+				n.headings = append(n.headings, docHeading{level: v.Level, text: t, slug: headingSlug(t), line: -1})
+			}
+			return ast.WalkSkipChildren, nil
+		case *ast.Link:
+			n.links = append(n.links, docLink{text: strings.TrimSpace(string(v.Text(src))), href: string(v.Destination), line: -1})
+			return ast.WalkSkipChildren, nil
+		case *ast.AutoLink:
+			u := string(v.URL(src))
+			n.links = append(n.links, docLink{text: string(v.Label(src)), href: u, line: -1})
+		}
+		return ast.WalkContinue, nil
+	})
+}
+
+// headingSlug builds a GitHub-style anchor for a heading.
+func headingSlug(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteByte('-')
+		}
+	}
+	return b.String()
+}
+
+// indexFold returns the rune offset of needle in hay ignoring case, or -1.
+func indexFold(hay []rune, needle []rune, from int) int {
+	if len(needle) == 0 {
+		return -1
+	}
+outer:
+	for i := from; i+len(needle) <= len(hay); i++ {
+		for j, r := range needle {
+			if unicode.ToLower(hay[i+j]) != unicode.ToLower(r) {
+				continue outer
+			}
+		}
+		return i
+	}
+	return -1
+}
+
+// locate maps headings and links onto rendered lines. Both appear in document
+// order, so each search continues from the previous hit.
+func (n *docNav) locate(rendered string) {
+	n.plain = strings.Split(ansi.Strip(rendered), "\n")
+	find := func(s string, from int) (int, int) {
+		needle := []rune(strings.TrimSpace(s))
+		for i := from; i < len(n.plain); i++ {
+			if col := indexFold([]rune(n.plain[i]), needle, 0); col >= 0 {
+				return i, col
+			}
+		}
+		return -1, -1
+	}
+	from := 0
+	for i := range n.headings {
+		if l, _ := find(n.headings[i].text, from); l >= 0 {
+			n.headings[i].line = l
+			from = l + 1
+		} else {
+			n.headings[i].line = -1
+		}
+	}
+	from = 0
+	for i := range n.links {
+		label := n.links[i].text
+		if label == "" {
+			label = n.links[i].href
+		}
+		if l, c := find(label, from); l >= 0 {
+			n.links[i].line, n.links[i].col = l, c
+			from = l
+		} else {
+			n.links[i].line = -1
+		}
+	}
+	n.search()
+}
+
+// search recomputes matches for the current query.
+func (n *docNav) search() {
+	n.matches = nil
+	n.cur = 0
+	q := []rune(n.query)
+	if len(q) == 0 {
+		return
+	}
+	for i, l := range n.plain {
+		rs := []rune(l)
+		for at := indexFold(rs, q, 0); at >= 0; at = indexFold(rs, q, at+len(q)) {
+			n.matches = append(n.matches, docMatch{line: i, start: at, end: at + len(q)})
+		}
+	}
+}
+
+// refreshNav re-locates the document structure in the rendered output and
+// applies highlights to the viewport content.
+func (m *MarkdownViewer) refreshNav() {
+	m.nav.locate(m.html)
+	m.applyHighlights()
+}
+
+// applyHighlights sets the viewport content, replacing lines containing
+// search matches or the selected link with highlighted plain text.
+func (m *MarkdownViewer) applyHighlights() {
+	type span struct {
+		start, end int
+		style      lipgloss.Style
+	}
+	spans := map[int][]span{}
+	for i, mt := range m.nav.matches {
+		st := navMatchStyle()
+		if i == m.nav.cur {
+			st = navCurStyle()
+		}
+		spans[mt.line] = append(spans[mt.line], span{mt.start, mt.end, st})
+	}
+	if l, ok := m.selectedLink(); ok && l.line >= 0 {
+		label := l.text
+		if label == "" {
+			label = l.href
+		}
+		spans[l.line] = append(spans[l.line], span{l.col, l.col + len([]rune(label)), navLinkStyle})
+	}
+	if len(spans) == 0 {
+		m.vp.SetContent(m.html)
+		return
+	}
+	lines := strings.Split(m.html, "\n")
+	for li, ss := range spans {
+		if li >= len(lines) || li >= len(m.nav.plain) {
+			continue
+		}
+		sort.Slice(ss, func(i, j int) bool { return ss[i].start < ss[j].start })
+		rs := []rune(m.nav.plain[li])
+		var b strings.Builder
+		pos := 0
+		for _, s := range ss {
+			if s.start < pos || s.end > len(rs) {
+				continue
+			}
+			b.WriteString(string(rs[pos:s.start]))
+			b.WriteString(s.style.Render(string(rs[s.start:s.end])))
+			pos = s.end
+		}
+		b.WriteString(string(rs[pos:]))
+		lines[li] = b.String()
+	}
+	m.vp.SetContent(strings.Join(lines, "\n"))
+}
+
+// selectedLink returns the link selected with [ and ].
+func (m *MarkdownViewer) selectedLink() (docLink, bool) {
+	if m.nav.linkSel < 0 || m.nav.linkSel >= len(m.nav.links) {
+		return docLink{}, false
+	}
+	return m.nav.links[m.nav.linkSel], true
+}
+
+// scrollTo brings line into view, leaving a little context above it.
+func (m *MarkdownViewer) scrollTo(line int) {
+	if line < 0 {
+		return
+	}
+	m.vp.SetYOffset(intMax(0, line-2))
+}
+
+// Captures reports whether the viewer wants to handle key itself rather than
+// letting the app interpret it as a global shortcut.
+func (m *MarkdownViewer) Captures(key tea.KeyMsg) bool {
+	if key.Type == tea.KeyCtrlC {
+		return false
+	}
+	if m.nav.searching || m.nav.outlineOpen {
+		return true
+	}
+	switch key.String() {
+	case "/", "n", "N", "[", "]":
+		return true
+	case "o":
+		return len(m.nav.headings) > 0
+	case "enter":
+		return m.nav.linkSel >= 0
+	case "esc":
+		return m.nav.query != "" || m.nav.linkSel >= 0
+	}
+	return false
+}
+
+// handleNavKey processes search, outline and link keys.
+func (m *MarkdownViewer) handleNavKey(key tea.KeyMsg) tea.Cmd {
+	n := &m.nav
+	if n.searching {
+		switch key.Type {
+		case tea.KeyEnter:
+			n.searching = false
+			n.input.Blur()
+			n.query = n.input.Value()
+			n.search()
+			m.jumpToMatch()
+			return nil
+		case tea.KeyEsc:
+			n.searching = false
+			n.input.Blur()
+			return nil
+		}
+		var cmd tea.Cmd
+		n.input, cmd = n.input.Update(key)
+		return cmd
+	}
+	if n.outlineOpen {
+		switch key.String() {
+		case "up", "k":
+			if n.outlineSel > 0 {
+				n.outlineSel--
+			}
+		case "down", "j":
+			if n.outlineSel < len(n.headings)-1 {
+				n.outlineSel++
+			}
+		case "enter":
+			n.outlineOpen = false
+			if n.outlineSel < len(n.headings) {
+				m.scrollTo(n.headings[n.outlineSel].line)
+			}
+		case "esc", "o", "q":
+			n.outlineOpen = false
+		}
+		return nil
+	}
+	switch key.String() {
+	case "/":
+		n.searching = true
+		n.input.SetValue(n.query)
+		n.input.CursorEnd()
+		return n.input.Focus()
+	case "n", "N":
+		if len(n.matches) == 0 {
+			return nil
+		}
+		if key.String() == "n" {
+			n.cur = (n.cur + 1) % len(n.matches)
+		} else {
+			n.cur = (n.cur - 1 + len(n.matches)) % len(n.matches)
+		}
+		m.jumpToMatch()
+	case "o":
+		n.outlineOpen = true
+		// preselect the section currently at the top of the viewport
+		n.outlineSel = 0
+		for i, h := range n.headings {
+			if h.line >= 0 && h.line <= m.vp.YOffset+1 {
+				n.outlineSel = i
+			}
+		}
+	case "]", "[":
+		if len(n.links) == 0 {
+			return nil
+		}
+		if key.String() == "]" {
+			n.linkSel = (n.linkSel + 1) % len(n.links)
+		} else if n.linkSel <= 0 {
+			n.linkSel = len(n.links) - 1
+		} else {
+			n.linkSel--
+		}
+		m.applyHighlights()
+		if l, ok := m.selectedLink(); ok {
+			if l.line < m.vp.YOffset || l.line >= m.vp.YOffset+m.vp.Height-1 {
+				m.scrollTo(l.line)
+			}
+		}
+	case "enter":
+		l, ok := m.selectedLink()
+		if !ok {
+			return nil
+		}
+		// In-page anchors jump to the matching heading
+		if strings.HasPrefix(l.href, "#") {
+			slug := strings.ToLower(strings.TrimPrefix(l.href, "#"))
+			for _, h := range n.headings {
+				if h.slug == slug {
+					m.scrollTo(h.line)
+					break
+				}
+			}
+			return nil
+		}
+		href := l.href
+		return func() tea.Msg { return ReadmeLinkMsg{Href: href} }
+	case "esc":
+		n.query = ""
+		n.matches = nil
+		n.linkSel = -1
+		m.applyHighlights()
+	}
+	return nil
+}
+
+// jumpToMatch highlights and scrolls to the current match.
+func (m *MarkdownViewer) jumpToMatch() {
+	m.applyHighlights()
+	if m.nav.cur < len(m.nav.matches) {
+		m.scrollTo(m.nav.matches[m.nav.cur].line)
+	}
+}
+
+// navFooter renders the one-line status shown under the document.
+func (m *MarkdownViewer) navFooter(w int) string {
+	n := &m.nav
+	var s string
+	switch {
+	case n.searching:
+		n.input.Width = intMax(1, w-2)
+		return n.input.View()
+	case n.outlineOpen:
+		s = "outline · ↑/↓ select · enter jump · esc close"
+	case n.query != "" && len(n.matches) == 0:
+		s = fmt.Sprintf("no matches for %q · / search · esc clear", n.query)
+	case n.query != "":
+		s = fmt.Sprintf("match %d/%d for %q · n/N next/prev · esc clear", n.cur+1, len(n.matches), n.query)
+	case n.linkSel >= 0:
+		l, _ := m.selectedLink()
+		s = fmt.Sprintf("link %d/%d: %s · enter open · [/] cycle · esc clear", n.linkSel+1, len(n.links), l.href)
+	default:
+		parts := []string{"/ search"}
+		if len(n.headings) > 0 {
+			parts = append(parts, "o outline")
+		}
+		if len(n.links) > 0 {
+			parts = append(parts, "[/] links")
+		}
+		s = strings.Join(parts, " · ")
+	}
+	return navHintStyle().Render(truncate(s, w))
+}
+
+// outlineView renders the heading outline in place of the document.
+func (m *MarkdownViewer) outlineView(w, h int) string {
+	n := &m.nav
+	title := lipgloss.NewStyle().Foreground(theme.Mauve).Bold(true).Render("Outline")
+	rows := []string{title, ""}
+	avail := intMax(1, h-2)
+	start := 0
+	if n.outlineSel >= avail {
+		start = n.outlineSel - avail + 1
+	}
+	for i := start; i < len(n.headings) && i < start+avail; i++ {
+		hd := n.headings[i]
+		line := strings.Repeat("  ", intMax(0, hd.level-1)) + hd.text
+		line = truncate(line, intMax(1, w-2))
+		if i == n.outlineSel {
+			rows = append(rows, lipgloss.NewStyle().Foreground(theme.Base).Background(theme.Mauve).Render("› "+line))
+		} else {
+			rows = append(rows, lipgloss.NewStyle().Foreground(theme.Text).Render("  "+line))
+		}
+	}
+	return strings.Join(rows, "\n")
+}
@@ -0,0 +1,245 @@
+package components
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// DocFormat is the markup language of a document shown in MarkdownViewer.
+type DocFormat int
+
+const (
+	FormatMarkdown DocFormat = iota
+	FormatRST
+	FormatPlain
+)
+
+// DetectFormat guesses a document's format from its file name. Unknown or
+// missing names are treated as Markdown since that is what npm renders.
+func DetectFormat(filename string) DocFormat {
+	base := strings.ToLower(path.Base(filename))
+	switch ext := path.Ext(base); ext {
+	case ".rst", ".rest":
+		return FormatRST
+	case ".txt", ".text":
+		return FormatPlain
+	case "":
+		// A bare README (no extension) is conventionally plain text
+		if base == "readme" {
+			return FormatPlain
+		}
+	}
+	return FormatMarkdown
+}
+
+var (
+	rstNamedLink   = regexp.MustCompile("`([^`<]+?)\\s*<([^>`]+)>`__?")
+	rstRefLink     = regexp.MustCompile("`([^`]+)`__?")
+	rstWordRef     = regexp.MustCompile(`\b([A-Za-z0-9][\w-]*)__?\b`)
+	rstLiteral     = regexp.MustCompile("``([^`]+)``")
+	rstTarget      = regexp.MustCompile(`^\.\.\s+_([^:]+):\s*(\S+)\s*$`)
+	rstDirective   = regexp.MustCompile(`^\.\.\s+([\w-]+)::\s*(.*)$`)
+	rstEnumerated  = regexp.MustCompile(`^(\s*)(?:\d+|#|[a-zA-Z])[.)]\s+`)
+	rstRoleLiteral = regexp.MustCompile(":(?:code|literal|file|command):`([^`]+)`")
+)
+
+// rstAdornment reports whether line is a section adornment (a repeated
+// punctuation character) and returns that character.
+func rstAdornment(line string) (byte, bool) {
+	line = strings.TrimRight(line, " ")
+	if len(line) < 2 {
+		return 0, false
+	}
+	c := line[0]
+	if !strings.ContainsRune("=-~^\"'`#*+:._<>", rune(c)) {
+		return 0, false
+	}
+	for i := 1; i < len(line); i++ {
+		if line[i] != c {
+			return 0, false
+		}
+	}
+	return c, true
+}
+
+// RSTToMarkdown converts the common subset of reStructuredText used in
+// READMEs (section titles, literal/code blocks, lists, links, inline
+// literals and images) to Markdown. Unsupported directives are dropped.
+func RSTToMarkdown(src string) string {
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+
+	// First pass: collect hyperlink targets (.. _name: url)
+	targets := map[string]string{}
+	for _, l := range lines {
+		if m := rstTarget.FindStringSubmatch(l); m != nil {
+			targets[strings.ToLower(strings.TrimSpace(m[1]))] = m[2]
+		}
+	}
+
+	var out []string
+	// heading styles in order of first appearance define heading levels
+	var styles []string
+	level := func(style string) int {
+		for i, s := range styles {
+			if s == style {
+				return i + 1
+			}
+		}
+		styles = append(styles, style)
+		return len(styles)
+	}
+	indentOf := func(s string) int { return len(s) - len(strings.TrimLeft(s, " \t")) }
+
+	// readBlock consumes the indented block starting at i (skipping leading
+	// blank lines) and returns it dedented plus the next line index.
+	readBlock := func(i int) ([]string, int) {
+		for i < len(lines) && strings.TrimSpace(lines[i]) == "" {
+			i++
+		}
+		var block []string
+		minIndent := -1
+		j := i
+		for ; j < len(lines); j++ {
+			l := lines[j]
+			if strings.TrimSpace(l) == "" {
+				block = append(block, "")
+				continue
+			}
+			ind := indentOf(l)
+			if ind == 0 {
+				break
+			}
+			if minIndent < 0 || ind < minIndent {
+				minIndent = ind
+			}
+			block = append(block, l)
+		}
+		for k, l := range block {
+			if len(l) >= minIndent && minIndent > 0 {
+				block[k] = l[minIndent:]
+			}
+		}
+		// trim trailing blanks
+		for len(block) > 0 && block[len(block)-1] == "" {
+			block = block[:len(block)-1]
+		}
+		return block, j
+	}
+	fence := func(lang string, body []string) {
+		out = append(out, "```"+lang)
+		out = append(out, body...)
+		out = append(out, "```", "")
+	}
+
+	for i := 0; i < len(lines); i++ {
+		l := lines[i]
+		trimmed := strings.TrimSpace(l)
+
+		// Overlined title: ====\nTitle\n====
+		if c, ok := rstAdornment(l); ok && i+2 < len(lines) {
+			if c2, ok2 := rstAdornment(lines[i+2]); ok2 && c2 == c && strings.TrimSpace(lines[i+1]) != "" {
+				lvl := level("over" + string(c))
+				out = append(out, strings.Repeat("#", min(6, lvl))+" "+strings.TrimSpace(lines[i+1]), "")
+				i += 2
+				continue
+			}
+		}
+		// Underlined title: Title\n=====
+		if trimmed != "" && indentOf(l) == 0 && i+1 < len(lines) {
+			if c, ok := rstAdornment(lines[i+1]); ok && len(strings.TrimRight(lines[i+1], " ")) >= len([]rune(trimmed)) {
+				lvl := level(string(c))
+				out = append(out, strings.Repeat("#", min(6, lvl))+" "+convertRSTInline(trimmed, targets), "")
+				i++
+				continue
+			}
+		}
+		// Directives and comments
+		if strings.HasPrefix(trimmed, "..") && indentOf(l) == 0 {
+			if rstTarget.MatchString(l) {
+				continue
+			}
+			if m := rstDirective.FindStringSubmatch(l); m != nil {
+				body, next := readBlock(i + 1)
+				switch m[1] {
+				case "code-block", "code", "sourcecode":
+					// drop directive options like ":linenos:"
+					for len(body) > 0 && strings.HasPrefix(strings.TrimSpace(body[0]), ":") {
+						body = body[1:]
+					}
+					for len(body) > 0 && body[0] == "" {
+						body = body[1:]
+					}
+					fence(strings.TrimSpace(m[2]), body)
+				case "image", "figure":
+					alt := "image"
+					for _, b := range body {
+						if v, ok := strings.CutPrefix(strings.TrimSpace(b), ":alt:"); ok {
+							alt = strings.TrimSpace(v)
+						}
+					}
+					out = append(out, "!["+alt+"]("+strings.TrimSpace(m[2])+")", "")
+				case "note", "warning", "tip", "important", "caution", "attention", "danger", "hint":
+					text := strings.TrimSpace(m[2])
+					out = append(out, "> **"+strings.ToUpper(m[1][:1])+m[1][1:]+":** "+convertRSTInline(text, targets))
+					for _, b := range body {
+						out = append(out, "> "+convertRSTInline(b, targets))
+					}
+					out = append(out, "")
+				}
+				i = next - 1
+				continue
+			}
+			// Comment: skip it and its indented body
+			_, next := readBlock(i + 1)
+			i = next - 1
+			continue
+		}
+		// Literal block introduced by a trailing "::"
+		if strings.HasSuffix(trimmed, "::") {
+			text := strings.TrimSuffix(l, "::")
+			if strings.TrimSpace(text) != "" {
+				// "Paragraph::" renders as "Paragraph:"
+				if strings.HasSuffix(text, " ") {
+					text = strings.TrimRight(text, " ")
+				} else {
+					text += ":"
+				}
+				out = append(out, convertRSTInline(text, targets), "")
+			}
+			body, next := readBlock(i + 1)
+			fence("", body)
+			i = next - 1
+			continue
+		}
+		// Enumerated lists (#. item / 1) item) become Markdown "1." items
+		if m := rstEnumerated.FindStringSubmatch(l); m != nil {
+			l = m[1] + "1. " + l[len(m[0]):]
+		}
+		out = append(out, convertRSTInline(l, targets))
+	}
+	return strings.Join(out, "\n")
+}
+
+// convertRSTInline rewrites inline literals and hyperlink references.
+func convertRSTInline(s string, targets map[string]string) string {
+	// Protect inline literals from link rewriting by converting them first
+	s = rstLiteral.ReplaceAllString(s, "`$1`")
+	s = rstRoleLiteral.ReplaceAllString(s, "`$1`")
+	s = rstNamedLink.ReplaceAllString(s, "[$1]($2)")
+	s = rstRefLink.ReplaceAllStringFunc(s, func(m string) string {
+		sub := rstRefLink.FindStringSubmatch(m)
+		if u, ok := targets[strings.ToLower(sub[1])]; ok {
+			return "[" + sub[1] + "](" + u + ")"
+		}
+		return m
+	})
+	s = rstWordRef.ReplaceAllStringFunc(s, func(m string) string {
+		word := strings.TrimRight(m, "_")
+		if u, ok := targets[strings.ToLower(word)]; ok {
+			return "[" + word + "](" + u + ")"
+		}
+		return m
+	})
+	return s
+}
@@ -0,0 +1,156 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/fredrikmwold/npm-tui/internal/ui/theme"
+)
+
+// ToastLevel grades a toast notification.
+type ToastLevel int
+
+const (
+	ToastSuccess ToastLevel = iota
+	ToastWarning
+	ToastError
+)
+
+// toastDuration is how long a toast shows; errors stay longer.
+func toastDuration(l ToastLevel) time.Duration {
+	if l == ToastError {
+		return 6 * time.Second
+	}
+	return 3 * time.Second
+}
+
+// toast is a queued notification.
+type toast struct {
+	id    int
+	level ToastLevel
+	text  string
+}
+
+// ToastExpiredMsg ends the toast with the same ID.
+type ToastExpiredMsg struct {
+	ID int
+}
+
+// StatusBar is the bottom line showing the project's package manager, path,
+// registry and connectivity, and transient toasts one at a time.
+type StatusBar struct {
+	width int
+
+	packageManager string
+	path           string
+	registry       string
+	online         bool
+	// checked is set once connectivity is known
+	checked bool
+
+	queue  []toast
+	nextID int
+}
+
+func NewStatusBar() *StatusBar { return &StatusBar{} }
+
+// Height is the number of lines the bar takes.
+func (s *StatusBar) Height() int { return 1 }
+
+func (s *StatusBar) SetWidth(w int) { s.width = w }
+
+// SetProject sets the package manager and project path.
+func (s *StatusBar) SetProject(packageManager, path string) {
+	s.packageManager, s.path = packageManager, path
+}
+
+// SetRegistry sets the registry shown in the bar.
+func (s *StatusBar) SetRegistry(r string) { s.registry = r }
+
+// SetOnline records whether the registry is reachable.
+func (s *StatusBar) SetOnline(online bool) {
+	s.online, s.checked = online, true
+}
+
+// Push queues a toast. The returned command expires it when it is the only
+// one; later toasts show when the earlier ones expire.
+func (s *StatusBar) Push(level ToastLevel, text string) tea.Cmd {
+	// Collapse repeats of the last queued toast
+	if n := len(s.queue); n > 0 && s.queue[n-1].text == text && s.queue[n-1].level == level {
+		return nil
+	}
+	s.nextID++
+	s.queue = append(s.queue, toast{id: s.nextID, level: level, text: text})
+	if len(s.queue) == 1 {
+		return s.expire(s.queue[0])
+	}
+	return nil
+}
+
+func (s *StatusBar) expire(t toast) tea.Cmd {
+	return tea.Tick(toastDuration(t.level), func(time.Time) tea.Msg { return ToastExpiredMsg{ID: t.id} })
+}
+
+func (s *StatusBar) Update(msg tea.Msg) tea.Cmd {
+	if m, ok := msg.(ToastExpiredMsg); ok && len(s.queue) > 0 && s.queue[0].id == m.ID {
+		s.queue = s.queue[1:]
+		if len(s.queue) > 0 {
+			return s.expire(s.queue[0])
+		}
+	}
+	return nil
+}
+
+func (s *StatusBar) View() string {
+	muted := lipgloss.NewStyle().Foreground(theme.Subtext0)
+	sep := lipgloss.NewStyle().Foreground(theme.Surface2).Render(" │ ")
+	var parts []string
+	if s.packageManager != "" {
+		parts = append(parts, lipgloss.NewStyle().Foreground(theme.Mauve).Bold(true).Render(s.packageManager))
+	}
+	if s.path != "" {
+		parts = append(parts, muted.Render(s.path))
+	}
+	if s.registry != "" {
+		parts = append(parts, muted.Render(s.registry))
+	}
+	switch {
+	case !s.checked:
+		parts = append(parts, muted.Render("○ checking"))
+	case s.online:
+		parts = append(parts, lipgloss.NewStyle().Foreground(theme.Green).Render("● online"))
+	default:
+		parts = append(parts, lipgloss.NewStyle().Foreground(theme.Red).Bold(true).Render("● offline"))
+	}
+	left := " " + strings.Join(parts, sep)
+
+	right := ""
+	if len(s.queue) > 0 {
+		t := s.queue[0]
+		st := lipgloss.NewStyle().Foreground(theme.Crust).Bold(true).Padding(0, 1)
+		icon := "✓ "
+		switch t.level {
+		case ToastWarning:
+			st, icon = st.Background(theme.Yellow), "⚠ "
+		case ToastError:
+			st, icon = st.Background(theme.Red), "✗ "
+		default:
+			st = st.Background(theme.Green)
+		}
+		text := icon + strings.Join(strings.Fields(t.text), " ")
+		if more := len(s.queue) - 1; more > 0 {
+			text += fmt.Sprintf(" (+%d)", more)
+		}
+		// The toast wins over the project details on narrow screens
+		right = st.Render(truncate(text, intMax(1, s.width-4)))
+	}
+	rw := lipgloss.Width(right)
+	left = ansi.Truncate(left, intMax(0, s.width-rw-1), "…")
+	gap := intMax(0, s.width-lipgloss.Width(left)-rw)
+	return left + strings.Repeat(" ", gap) + right
+}
@@ -0,0 +1,474 @@
+package components
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/formatters"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/fredrikmwold/npm-tui/internal/ui/theme"
+)
+
+// TarballEntry is a file in the browsed tarball.
+type TarballEntry struct {
+	Path string
+	Size int64
+}
+
+// TarballOpenMsg asks the app to load a file from the tarball for viewing.
+type TarballOpenMsg struct {
+	Path string
+}
+
+// tarNode is a file or directory in the tarball tree.
+type tarNode struct {
+	name     string
+	path     string
+	dir      bool
+	size     int64 // file size, or the sum of all files below a directory
+	children []*tarNode
+}
+
+// tarRow is a visible line of the flattened tree.
+type tarRow struct {
+	node  *tarNode
+	depth int
+}
+
+// TarballBrowser shows the files of a published package as a tree with sizes
+// and opens files in a syntax-highlighted viewer.
+type TarballBrowser struct {
+	width  int
+	height int
+
+	title    string // name@version
+	files    int
+	total    int64
+	root     *tarNode
+	expanded map[string]bool
+	rows     []tarRow
+	cursor   int
+	offset   int
+	status   string // loading or error message replacing the tree
+	closeKey string // key that closes the browser, for the footer
+
+	// file viewer
+	viewing bool
+	file    string
+	src     []byte // raw content of file, re-highlighted on theme changes
+	vp      viewport.Model
+}
+
+func NewTarballBrowser() *TarballBrowser {
+	vp := viewport.New(0, 0)
+	vp.MouseWheelEnabled = true
+	return &TarballBrowser{vp: vp, expanded: map[string]bool{}, closeKey: "t"}
+}
+
+// SetCloseKey sets the key shown as closing the browser.
+func (t *TarballBrowser) SetCloseKey(k string) { t.closeKey = k }
+
+func (t *TarballBrowser) Init() tea.Cmd { return nil }
+
+func tarFrame() lipgloss.Style {
+	return lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(theme.BorderFocused).Foreground(theme.Text)
+}
+
+func (t *TarballBrowser) SetSize(w, h int) {
+	t.width, t.height = intMax(1, w), intMax(0, h)
+	iw, ih := t.innerSize()
+	// header and footer take one line each in the file view
+	t.vp.Width = iw
+	t.vp.Height = intMax(1, ih-2)
+	t.clampScroll()
+}
+
+// innerSize returns the content area inside the border.
+func (t *TarballBrowser) innerSize() (int, int) {
+	fw, fh := tarFrame().GetFrameSize()
+	return intMax(1, t.width-fw), intMax(1, t.height-fh)
+}
+
+// SetLoading replaces the tree with a loading message.
+func (t *TarballBrowser) SetLoading(label, spin string) {
+	t.viewing = false
+	t.root = nil
+	t.rows = nil
+	t.status = strings.TrimSpace(label + " " + spin)
+}
+
+// SetError replaces the tree with an error message.
+func (t *TarballBrowser) SetError(err error) {
+	t.root = nil
+	t.rows = nil
+	t.status = "Could not load tarball:\n\n" + err.Error()
+}
+
+// SetFiles builds the tree for name@version. Small packages start fully
+// expanded; larger ones show only the top level.
+func (t *TarballBrowser) SetFiles(name, version string, files []TarballEntry) {
+	t.title = name + "@" + version
+	t.status = ""
+	t.viewing = false
+	t.files = len(files)
+	t.total = 0
+	t.root = &tarNode{dir: true}
+	t.expanded = map[string]bool{}
+	dirs := map[string]*tarNode{"": t.root}
+	var mkdir func(p string) *tarNode
+	mkdir = func(p string) *tarNode {
+		if n, ok := dirs[p]; ok {
+			return n
+		}
+		parent := mkdir(parentDir(p))
+		n := &tarNode{name: path.Base(p), path: p, dir: true}
+		parent.children = append(parent.children, n)
+		dirs[p] = n
+		return n
+	}
+	for _, f := range files {
+		parent := mkdir(parentDir(f.Path))
+		parent.children = append(parent.children, &tarNode{name: path.Base(f.Path), path: f.Path, size: f.Size})
+		t.total += f.Size
+		for d := parent; ; d = dirs[parentDir(d.path)] {
+			d.size += f.Size
+			if d == t.root {
+				break
+			}
+		}
+	}
+	expandAll := len(files) <= 100
+	for p, n := range dirs {
+		// directories first, then files, each alphabetically
+		sort.Slice(n.children, func(i, j int) bool {
+			a, b := n.children[i], n.children[j]
+			if a.dir != b.dir {
+				return a.dir
+			}
+			return a.name < b.name
+		})
+		if expandAll {
+			t.expanded[p] = true
+		}
+	}
+	t.cursor, t.offset = 0, 0
+	t.flatten()
+}
+
+// parentDir returns the directory of p, or "" at the package root.
+func parentDir(p string) string {
+	d := path.Dir(p)
+	if d == "." || d == "/" {
+		return ""
+	}
+	return d
+}
+
+// flatten rebuilds the visible rows from the expanded directories.
+func (t *TarballBrowser) flatten() {
+	t.rows = t.rows[:0]
+	var walk func(n *tarNode, depth int)
+	walk = func(n *tarNode, depth int) {
+		for _, c := range n.children {
+			t.rows = append(t.rows, tarRow{node: c, depth: depth})
+			if c.dir && t.expanded[c.path] {
+				walk(c, depth+1)
+			}
+		}
+	}
+	if t.root != nil {
+		walk(t.root, 0)
+	}
+	if t.cursor >= len(t.rows) {
+		t.cursor = intMax(0, len(t.rows)-1)
+	}
+	t.clampScroll()
+}
+
+// treeHeight is the number of tree rows that fit below the summary lines.
+func (t *TarballBrowser) treeHeight() int {
+	_, ih := t.innerSize()
+	return intMax(1, ih-3)
+}
+
+func (t *TarballBrowser) clampScroll() {
+	h := t.treeHeight()
+	if t.cursor < t.offset {
+		t.offset = t.cursor
+	}
+	if t.cursor >= t.offset+h {
+		t.offset = t.cursor - h + 1
+	}
+	t.offset = intMax(0, t.offset)
+}
+
+// SetFileContent shows a file in the viewer with syntax highlighting.
+func (t *TarballBrowser) SetFileContent(p string, content []byte, err error) {
+	if !t.viewing || p != t.file {
+		return
+	}
+	if err != nil {
+		t.vp.SetContent("Could not read " + p + ":\n\n" + err.Error())
+		return
+	}
+	t.src = content
+	t.vp.SetContent(highlightSource(p, content))
+	t.vp.GotoTop()
+}
+
+// ApplyTheme re-highlights the open file with the active theme's colors.
+func (t *TarballBrowser) ApplyTheme() {
+	if !t.viewing || t.src == nil {
+		return
+	}
+	off := t.vp.YOffset
+	t.vp.SetContent(highlightSource(t.file, t.src))
+	t.vp.SetYOffset(off)
+}
+
+// Back leaves the file viewer. It reports false when the tree is already
+// showing so the caller can close the browser instead.
+func (t *TarballBrowser) Back() bool {
+	if !t.viewing {
+		return false
+	}
+	t.viewing = false
+	return true
+}
+
+func (t *TarballBrowser) Update(msg tea.Msg) tea.Cmd {
+	if t.viewing {
+		if k, ok := msg.(tea.KeyMsg); ok {
+			switch k.String() {
+			case "backspace", "left", "h", "q":
+				t.viewing = false
+				return nil
+			}
+		}
+		var cmd tea.Cmd
+		t.vp, cmd = t.vp.Update(msg)
+		return cmd
+	}
+	if len(t.rows) == 0 {
+		return nil
+	}
+	switch m := msg.(type) {
+	case tea.MouseMsg:
+		switch m.Type {
+		case tea.MouseWheelUp:
+			t.move(-3)
+		case tea.MouseWheelDown:
+			t.move(3)
+		}
+	case tea.KeyMsg:
+		switch m.String() {
+		case "up", "k":
+			t.move(-1)
+		case "down", "j":
+			t.move(1)
+		case "pgup":
+			t.move(-t.treeHeight())
+		case "pgdown":
+			t.move(t.treeHeight())
+		case "home", "g":
+			t.move(-len(t.rows))
+		case "end", "G":
+			t.move(len(t.rows))
+		case "left", "h":
+			row := t.rows[t.cursor]
+			if row.node.dir && t.expanded[row.node.path] {
+				t.expanded[row.node.path] = false
+				t.flatten()
+				return nil
+			}
+			// jump to the parent directory
+			parent := parentDir(row.node.path)
+			for i, r := range t.rows {
+				if r.node.path == parent && r.node.dir {
+					t.cursor = i
+					t.clampScroll()
+					break
+				}
+			}
+		case "right", "l", "enter":
+			n := t.rows[t.cursor].node
+			if n.dir {
+				if m.String() == "right" || m.String() == "l" {
+					t.expanded[n.path] = true
+				} else {
+					t.expanded[n.path] = !t.expanded[n.path]
+				}
+				t.flatten()
+				return nil
+			}
+			if m.String() != "enter" {
+				return nil
+			}
+			t.viewing = true
+			t.file = n.path
+			t.vp.SetContent(lipgloss.NewStyle().Foreground(theme.Subtext0).Render("Loading " + n.path + "…"))
+			t.vp.GotoTop()
+			p := n.path
+			return func() tea.Msg { return TarballOpenMsg{Path: p} }
+		}
+	}
+	return nil
+}
+
+func (t *TarballBrowser) move(d int) {
+	t.cursor = max(0, min(len(t.rows)-1, t.cursor+d))
+	t.clampScroll()
+}
+
+func (t *TarballBrowser) View() string {
+	iw, ih := t.innerSize()
+	headingStyle := lipgloss.NewStyle().Foreground(theme.Crust).Background(theme.Lavender).Bold(true).Padding(0, 1)
+	mutedStyle := lipgloss.NewStyle().Foreground(theme.Subtext0)
+	frame := tarFrame().Width(iw).Height(ih)
+
+	if t.status != "" && t.root == nil {
+		msg := mutedStyle.Render(t.status)
+		return frame.Render(lipgloss.Place(iw, ih, lipgloss.Center, lipgloss.Center, msg))
+	}
+	if t.viewing {
+		head := headingStyle.Render(t.title) + " " + lipgloss.NewStyle().Foreground(theme.Text).Bold(true).Render(truncate(t.file, intMax(1, iw-lipgloss.Width(t.title)-4)))
+		foot := mutedStyle.Render(truncate(fmt.Sprintf("%d%% · ↑/↓ scroll · esc/← back to files · %s close", int(t.vp.ScrollPercent()*100), t.closeKey), iw))
+		return frame.Render(lipgloss.JoinVertical(lipgloss.Left, head, t.vp.View(), foot))
+	}
+
+	var b strings.Builder
+	b.WriteString(headingStyle.Render(t.title))
+	b.WriteString(" ")
+	b.WriteString(mutedStyle.Render(fmt.Sprintf("%d files · %s · integrity verified", t.files, formatBytes(t.total))))
+	b.WriteString("\n")
+	b.WriteString(mutedStyle.Render(truncate(t.facts(), iw)))
+	b.WriteString("\n\n")
+
+	dirStyle := lipgloss.NewStyle().Foreground(theme.Blue).Bold(true)
+	fileStyle := lipgloss.NewStyle().Foreground(theme.Text)
+	selStyle := lipgloss.NewStyle().Foreground(theme.Base).Background(theme.Mauve)
+	const sizeW = 10
+	h := t.treeHeight()
+	for i := t.offset; i < len(t.rows) && i < t.offset+h; i++ {
+		r := t.rows[i]
+		icon := "  "
+		if r.node.dir {
+			icon = "▸ "
+			if t.expanded[r.node.path] {
+				icon = "▾ "
+			}
+		}
+		name := strings.Repeat("  ", r.depth) + icon + r.node.name
+		if r.node.dir {
+			name += "/"
+		}
+		name = truncate(name, intMax(1, iw-sizeW-1))
+		size := formatBytes(r.node.size)
+		pad := intMax(1, iw-lipgloss.Width(name)-lipgloss.Width(size))
+		line := name + strings.Repeat(" ", pad) + size
+		switch {
+		case i == t.cursor:
+			line = selStyle.Render(line)
+		case r.node.dir:
+			line = dirStyle.Render(line)
+		default:
+			line = fileStyle.Render(line)
+		}
+		b.WriteString(line)
+		if i < len(t.rows)-1 && i < t.offset+h-1 {
+			b.WriteString("\n")
+		}
+	}
+	return frame.Render(b.String())
+}
+
+// facts summarizes what ships in the package: type declarations, module
+// flavours and source maps.
+func (t *TarballBrowser) facts() string {
+	var dts, mjs, cjs, maps int
+	hasPkg := false
+	var walk func(n *tarNode)
+	walk = func(n *tarNode) {
+		for _, c := range n.children {
+			if c.dir {
+				walk(c)
+				continue
+			}
+			switch {
+			case strings.HasSuffix(c.name, ".d.ts"), strings.HasSuffix(c.name, ".d.mts"), strings.HasSuffix(c.name, ".d.cts"):
+				dts++
+			case strings.HasSuffix(c.name, ".mjs"):
+				mjs++
+			case strings.HasSuffix(c.name, ".cjs"):
+				cjs++
+			case strings.HasSuffix(c.name, ".map"):
+				maps++
+			}
+			if c.path == "package.json" {
+				hasPkg = true
+			}
+		}
+	}
+	if t.root != nil {
+		walk(t.root)
+	}
+	parts := []string{fmt.Sprintf("types: %d .d.ts", dts), fmt.Sprintf("%d .mjs", mjs), fmt.Sprintf("%d .cjs", cjs), fmt.Sprintf("%d source maps", maps)}
+	if hasPkg {
+		parts = append(parts, "open package.json for scripts/exports")
+	}
+	return strings.Join(parts, " · ")
+}
+
+// highlightSource renders a file with chroma using the active theme's code
+// style, prefixed with line numbers. Binary files are summarized.
+func highlightSource(p string, src []byte) string {
+	probe := src
+	if len(probe) > 8000 {
+		probe = probe[:8000]
+	}
+	if bytes.IndexByte(probe, 0) >= 0 {
+		return lipgloss.NewStyle().Foreground(theme.Subtext0).Render(fmt.Sprintf("Binary file (%s)", formatBytes(int64(len(src)))))
+	}
+	code := strings.ReplaceAll(string(src), "\t", "    ")
+	lexer := lexers.Match(path.Base(p))
+	if lexer == nil {
+		lexer = lexers.Analyse(code)
+	}
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+	lexer = chroma.Coalesce(lexer)
+	style := styles.Get(theme.Current().Chroma)
+	formatter := formatters.Get("terminal256")
+	// Format line by line so colors never bleed across the viewport's lines
+	lines := strings.Split(strings.TrimRight(code, "\n"), "\n")
+	if it, err := lexer.Tokenise(nil, code); err == nil {
+		var hl []string
+		for _, toks := range chroma.SplitTokensIntoLines(it.Tokens()) {
+			var buf bytes.Buffer
+			if err := formatter.Format(&buf, style, chroma.Literator(toks...)); err != nil {
+				hl = nil
+				break
+			}
+			hl = append(hl, strings.ReplaceAll(buf.String(), "\n", ""))
+		}
+		if len(hl) >= len(lines) {
+			lines = hl[:len(lines)]
+		}
+	}
+	gutter := lipgloss.NewStyle().Foreground(theme.Surface2)
+	w := len(fmt.Sprint(len(lines)))
+	for i, l := range lines {
+		lines[i] = gutter.Render(fmt.Sprintf("%*d ", w, i+1)) + l
+	}
+	return strings.Join(lines, "\n")
+}
@@ -1,5 +1,10 @@
 package components
 
+import (
+	"fmt"
+	"math"
+)
+
 // intMax returns the larger of a and b.
 func intMax(a, b int) int {
 	if a > b {
@@ -7,3 +12,47 @@ func intMax(a, b int) int {
 	}
 	return b
 }
+
+// compactNumber formats large values with a short suffix (e.g., 1.2k, 3.4M).
+func compactNumber(v float64) string {
+	av := math.Abs(v)
+	switch {
+	case av >= 1_000_000_000:
+		return fmt.Sprintf("%.1fB", v/1_000_000_000)
+	case av >= 1_000_000:
+		return fmt.Sprintf("%.1fM", v/1_000_000)
+	case av >= 1_000:
+		return fmt.Sprintf("%.0fk", v/1_000)
+	default:
+		return fmt.Sprintf("%.0f", v)
+	}
+}
+
+// formatBytes renders a byte count using binary units (e.g., 12.3 kB).
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "kMGTPE"[exp])
+}
+
+// truncate shortens s to at most w runes, adding an ellipsis when cut.
+func truncate(s string, w int) string {
+	r := []rune(s)
+	if w <= 0 {
+		return ""
+	}
+	if len(r) <= w {
+		return s
+	}
+	if w == 1 {
+		return "…"
+	}
+	return string(r[:w-1]) + "…"
+}
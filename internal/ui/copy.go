@@ -0,0 +1,56 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/fredrikmwold/npm-tui/internal/commands"
+	"github.com/fredrikmwold/npm-tui/internal/ui/components"
+)
+
+// copyTargets lists what can be copied for the selected package.
+func (m *Model) copyTargets() []menuTarget {
+	det, ok := m.list.SelectedDetails()
+	if !ok {
+		return nil
+	}
+	version := det.InstalledVersion
+	if version == "" {
+		version = det.Latest
+	}
+	nameVersion := det.Name
+	if version != "" {
+		nameVersion += "@" + version
+	}
+	targets := []menuTarget{
+		{key: "i", what: "install command", text: commands.InstallCommand(det.Name, false, m.toolchain())},
+		{key: "d", what: "dev install command", text: commands.InstallCommand(det.Name, true, m.toolchain())},
+		{key: "n", what: "name@version", text: nameVersion},
+	}
+	for _, l := range m.linkTargets() {
+		l.what += " URL"
+		targets = append(targets, l)
+	}
+	return targets
+}
+
+// openCopyMenu asks which text to copy with the next key.
+func (m *Model) openCopyMenu() {
+	m.openMenu("Copy", m.copyTargets(), copyTarget)
+}
+
+// copyTarget puts t on the clipboard.
+func copyTarget(t menuTarget) tea.Cmd {
+	return commands.CopyToClipboard(t.text, t.what)
+}
+
+// copyToast describes the result of a copy for the status bar.
+func copyToast(msg commands.CopyMsg) (components.ToastLevel, string) {
+	if msg.Err != nil {
+		return components.ToastError, "Copy failed: " + msg.Err.Error()
+	}
+	text := "Copied " + msg.What
+	if msg.OSC52 {
+		text += " (via terminal)"
+	}
+	return components.ToastSuccess, text
+}
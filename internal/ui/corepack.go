@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/fredrikmwold/npm-tui/internal/commands"
+	"github.com/fredrikmwold/npm-tui/internal/ui/components"
+)
+
+// setProject records the detected project and warns when the package
+// manager on PATH is not the version package.json declares.
+func (m *Model) setProject(msg commands.ProjectInfoMsg) tea.Cmd {
+	m.project = msg
+	m.showProjectStatus()
+	if !msg.Mismatch() {
+		return nil
+	}
+	text := fmt.Sprintf("package.json declares %s@%s but %s", msg.PackageManager, msg.Declared, installedVersion(msg))
+	if msg.Corepack {
+		text += "; run it through corepack from the command palette (ctrl+p)"
+	}
+	return m.toast(components.ToastWarning, text)
+}
+
+// installedVersion describes the package manager on PATH, e.g.
+// "pnpm 8.15.0 is installed".
+func installedVersion(msg commands.ProjectInfoMsg) string {
+	if msg.Installed == "" {
+		return string(msg.PackageManager) + " is not installed"
+	}
+	return fmt.Sprintf("%s %s is installed", msg.PackageManager, msg.Installed)
+}
+
+// toolchain returns the package manager installs use, from the project
+// detected at startup; before detection finishes it falls back to the
+// configured package manager, or npm.
+func (m *Model) toolchain() commands.Toolchain {
+	if m.project.PackageManager != "" {
+		return m.project.Toolchain(m.corepack)
+	}
+	if m.packageManager != "" {
+		return commands.Toolchain{PM: m.packageManager}
+	}
+	return commands.Toolchain{PM: commands.PMNPM}
+}
+
+// canUseCorepack reports whether installs can switch to corepack: the
+// project pins a version that differs from the installed one, and corepack
+// is available. Switching back is always possible.
+func (m *Model) canUseCorepack() bool {
+	return m.corepack || (m.project.Mismatch() && m.project.Corepack)
+}
+
+// toggleCorepack switches installs and copied commands between corepack and
+// the package manager on PATH.
+func (m *Model) toggleCorepack() tea.Cmd {
+	m.corepack = !m.corepack
+	m.showProjectStatus()
+	if m.corepack {
+		return m.toast(components.ToastSuccess, fmt.Sprintf("Installs run %s@%s through corepack", m.project.PackageManager, m.project.Declared))
+	}
+	return m.toast(components.ToastSuccess, "Installs use the "+string(m.project.PackageManager)+" on PATH")
+}
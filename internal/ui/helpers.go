@@ -3,6 +3,8 @@ package ui
 import (
 	"fmt"
 	"math"
+
+	"github.com/fredrikmwold/npm-tui/internal/commands"
 )
 
 // UI formatting and layout helpers
@@ -54,3 +56,23 @@ func computeSplit(total int, open bool) (listW, sideW int) {
 	}
 	return total - side, side
 }
+
+// nextWindow returns the downloads window following w in cycling order.
+func nextWindow(w commands.DownloadsWindow) commands.DownloadsWindow {
+	for i, c := range commands.DownloadsWindows {
+		if c == w {
+			return commands.DownloadsWindows[(i+1)%len(commands.DownloadsWindows)]
+		}
+	}
+	return commands.DownloadsWindows[0]
+}
+
+// nextGranularity returns the granularity following g in cycling order.
+func nextGranularity(g commands.Granularity) commands.Granularity {
+	for i, c := range commands.Granularities {
+		if c == g {
+			return commands.Granularities[(i+1)%len(commands.Granularities)]
+		}
+	}
+	return commands.Granularities[0]
+}
@@ -0,0 +1,124 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/fredrikmwold/npm-tui/internal/commands"
+	"github.com/fredrikmwold/npm-tui/internal/ui/components"
+)
+
+// paletteQuery prefixes saved query names in the saved query picker.
+const paletteQuery = "query:"
+
+// setHistory replaces the search history and refreshes the input's recall
+// and suggestions.
+func (m *Model) setHistory(h commands.History) {
+	m.history = h
+	m.input.SetHistory(slices.Clone(h.Searches))
+	// Saved queries first, then past searches, most recent first
+	sugg := make([]components.InputSuggestion, 0, len(h.Saved)+len(h.Searches))
+	for _, s := range h.Saved {
+		sugg = append(sugg, components.InputSuggestion{Label: s.Name, Value: s.Query})
+	}
+	for i := len(h.Searches) - 1; i >= 0; i-- {
+		sugg = append(sugg, components.InputSuggestion{Value: h.Searches[i]})
+	}
+	m.input.SetSuggestions(sugg)
+}
+
+// loadHistory takes the history read from disk, keeping searches and saved
+// queries made while it was loading, and allows saving from now on.
+func (m *Model) loadHistory(h commands.History) tea.Cmd {
+	early := m.history
+	for _, q := range early.Searches {
+		h.AddSearch(q)
+	}
+	for _, s := range early.Saved {
+		h.Save(s.Name, s.Query)
+	}
+	m.historyLoaded = true
+	m.setHistory(h)
+	if len(early.Searches) == 0 && len(early.Saved) == 0 {
+		return nil
+	}
+	return m.saveHistory(h)
+}
+
+// saveHistory writes h once the history file was read.
+func (m *Model) saveHistory(h commands.History) tea.Cmd {
+	if !m.historyLoaded {
+		return nil
+	}
+	return commands.SaveHistory(h)
+}
+
+// recordSearch adds q to the history and saves it.
+func (m *Model) recordSearch(q string) tea.Cmd {
+	h := m.history
+	h.AddSearch(q)
+	m.setHistory(h)
+	return m.saveHistory(h)
+}
+
+// startNaming asks for a name for the query in the input; the input takes
+// the name until Enter saves it or Esc cancels.
+func (m *Model) startNaming() {
+	q := strings.TrimSpace(m.input.Value())
+	if q == "" {
+		return
+	}
+	m.naming = &q
+	m.input.Clear()
+}
+
+// handleNamingKey feeds msg to the name being typed.
+func (m *Model) handleNamingKey(msg tea.KeyMsg) tea.Cmd {
+	q := *m.naming
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.naming = nil
+		m.input.SetValue(q)
+		return nil
+	case tea.KeyEnter:
+		name := strings.TrimSpace(m.input.Value())
+		m.naming = nil
+		m.input.SetValue(q)
+		if name == "" {
+			return nil
+		}
+		h := m.history
+		h.Save(name, q)
+		m.setHistory(h)
+		return tea.Batch(m.saveHistory(h), m.toast(components.ToastSuccess, "Saved query "+name))
+	case tea.KeyUp, tea.KeyDown:
+		// Names are not recalled from the search history
+		return nil
+	}
+	return m.input.Update(msg)
+}
+
+// openQueryPicker lists the saved queries in the palette.
+func (m *Model) openQueryPicker() tea.Cmd {
+	if len(m.history.Saved) == 0 {
+		return m.toast(components.ToastWarning, "No saved queries; save one with ctrl+s in the search input")
+	}
+	items := make([]components.PaletteItem, 0, len(m.history.Saved))
+	for _, s := range m.history.Saved {
+		items = append(items, components.PaletteItem{ID: paletteQuery + s.Name, Title: s.Name, Key: s.Query})
+	}
+	return m.palette.Open("Type to search saved queries", items)
+}
+
+// runSavedQuery searches for the saved query called name.
+func (m *Model) runSavedQuery(name string) tea.Cmd {
+	for _, s := range m.history.Saved {
+		if s.Name == name {
+			m.input.SetValue(s.Query)
+			return m.search(s.Query)
+		}
+	}
+	return nil
+}
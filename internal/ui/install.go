@@ -0,0 +1,208 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/fredrikmwold/npm-tui/internal/commands"
+	"github.com/fredrikmwold/npm-tui/internal/ui/components"
+	"github.com/fredrikmwold/npm-tui/internal/ui/theme"
+)
+
+// pendingInstall is an install waiting for pre-install checks or for the
+// user to confirm the warnings they produced.
+type pendingInstall struct {
+	name string
+	dev  bool
+	// warnings explain why confirmation is required
+	warnings []string
+	// blocked is set for license policy violations; enforced ones cannot be
+	// overridden
+	blocked  bool
+	enforced bool
+}
+
+// requestInstall runs the pre-install checks for name and either starts the
+// install, asks for confirmation, or waits for the checks to finish.
+func (m *Model) requestInstall(name string, dev bool) tea.Cmd {
+	lic := m.licensePolicy.Check(m.licenses[name])
+	if lic.Violation && m.licensePolicy.Enforce {
+		m.confirm = &pendingInstall{name: name, dev: dev, warnings: []string{licenseWarning(lic)}, blocked: true, enforced: true}
+		m.recomputeLayout()
+		return nil
+	}
+	r, riskOK := m.risks[name]
+	sq, squatOK := m.squats[name]
+	if !riskOK || !squatOK {
+		// Show the row spinner while the checks run, then decide
+		m.awaiting = &pendingInstall{name: name, dev: dev}
+		m.installing[name] = true
+		m.list.SetInstalling(m.installing)
+		return tea.Batch(m.fetchRisk(name), m.checkTyposquat(name))
+	}
+	warnings := installWarnings(r)
+	if sq.Suggestion != "" {
+		// The likely intended package goes first; it is the most actionable
+		warnings = append([]string{typosquatWarning(sq)}, warnings...)
+	}
+	if lic.Violation {
+		warnings = append([]string{licenseWarning(lic)}, warnings...)
+	}
+	if len(warnings) > 0 {
+		m.confirm = &pendingInstall{name: name, dev: dev, warnings: warnings, blocked: lic.Violation}
+		m.recomputeLayout()
+		return nil
+	}
+	return m.startInstall(name, dev)
+}
+
+// startInstall marks the row as installing and runs the package manager.
+func (m *Model) startInstall(name string, dev bool) tea.Cmd {
+	if m.installing == nil {
+		m.installing = map[string]bool{}
+	}
+	m.installing[name] = true
+	m.list.SetInstalling(m.installing)
+	return commands.InstallNPM(name, dev, m.packageManager, m.corepack)
+}
+
+// resumeInstall continues an install that was waiting for pre-install checks.
+func (m *Model) resumeInstall(name string) tea.Cmd {
+	if m.awaiting == nil || m.awaiting.name != name {
+		return nil
+	}
+	p := *m.awaiting
+	m.awaiting = nil
+	delete(m.installing, name)
+	m.list.SetInstalling(m.installing)
+	return m.requestInstall(p.name, p.dev)
+}
+
+// fetchRisk requests risk signals for name unless already known or in flight.
+func (m *Model) fetchRisk(name string) tea.Cmd {
+	if _, ok := m.risks[name]; ok || m.riskPending[name] {
+		return nil
+	}
+	m.riskPending[name] = true
+	return commands.FetchRiskSignals(name)
+}
+
+// checkTyposquat compares name to popular packages unless already checked
+// or in flight.
+func (m *Model) checkTyposquat(name string) tea.Cmd {
+	if _, ok := m.squats[name]; ok || m.squatPending[name] {
+		return nil
+	}
+	m.squatPending[name] = true
+	return commands.CheckTyposquat(name)
+}
+
+// typosquatWarning describes the likely intended package.
+func typosquatWarning(sq commands.TyposquatMsg) string {
+	ratio := sq.SuggestionDownloads / max(1, sq.Downloads)
+	return fmt.Sprintf("did you mean %s? (%s, %s× more weekly downloads)", sq.Suggestion, sq.Reason, fmtInt(ratio))
+}
+
+// licenseWarning describes a license policy violation.
+func licenseWarning(v commands.LicenseVerdict) string {
+	return "license " + nonEmpty(v.License) + " violates policy (" + v.Reason + ")"
+}
+
+// licenseIssue renders the sidebar note for name's license, empty when it
+// complies with the policy.
+func (m *Model) licenseIssue(name string) string {
+	if v := m.licensePolicy.Check(m.licenses[name]); v.Violation {
+		return licenseWarning(v)
+	}
+	return ""
+}
+
+// installWarnings lists the risk signals that need confirmation. Lookup
+// failures do not block installs; the package manager reports real errors.
+func installWarnings(r commands.RiskMsg) []string {
+	if !r.Risky() {
+		return nil
+	}
+	var w []string
+	if len(r.InstallScripts) > 0 {
+		names := make([]string, 0, len(r.InstallScripts))
+		for n := range r.InstallScripts {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		w = append(w, "runs "+strings.Join(names, "/")+" scripts")
+	}
+	if r.NewPublisher {
+		w = append(w, "published by a new account ("+r.Publisher+")")
+	}
+	if r.Recent {
+		w = append(w, fmt.Sprintf("%s is under 72h old", r.Version))
+	}
+	return w
+}
+
+// riskSignals maps fetched risk signals to the sidebar section.
+func riskSignals(r commands.RiskMsg) *components.RiskSignals {
+	if r.Err != nil {
+		return nil
+	}
+	s := &components.RiskSignals{
+		Version:      r.Version,
+		Publisher:    r.Publisher,
+		NewPublisher: r.NewPublisher,
+		PublishedAt:  r.PublishedAt,
+		Recent:       r.Recent,
+		Provenance:   r.Provenance,
+	}
+	for _, n := range []string{"preinstall", "install", "postinstall"} {
+		if cmd, ok := r.InstallScripts[n]; ok {
+			s.InstallScripts = append(s.InstallScripts, n+": "+cmd)
+		}
+	}
+	return s
+}
+
+// handleConfirmKey resolves a pending confirmation: y installs, anything
+// else cancels. Enforced license blocks only dismiss.
+func (m *Model) handleConfirmKey(msg tea.KeyMsg) tea.Cmd {
+	p := m.confirm
+	m.confirm = nil
+	m.recomputeLayout()
+	if !p.enforced && (msg.String() == "y" || msg.String() == "Y") {
+		return m.startInstall(p.name, p.dev)
+	}
+	return nil
+}
+
+// confirmView renders the confirmation prompt shown above the results.
+func (m *Model) confirmView() string {
+	if m.confirm == nil {
+		return ""
+	}
+	warn := lipgloss.NewStyle().Foreground(theme.Crust).Background(theme.Red).Bold(true).Padding(0, 1)
+	text := lipgloss.NewStyle().Foreground(theme.Red)
+	hint := lipgloss.NewStyle().Foreground(theme.Subtext0)
+	label, action := "⚠ "+m.confirm.name, "· y install anyway · any other key cancels"
+	switch {
+	case m.confirm.enforced:
+		label, action = "⛔ "+m.confirm.name+" blocked", "· any key dismisses"
+	case m.confirm.blocked:
+		label, action = "⛔ "+m.confirm.name+" blocked", "· y override · any other key cancels"
+	}
+	line := warn.Render(label) + " " +
+		text.Render(strings.Join(m.confirm.warnings, "; ")) + " " +
+		hint.Render(action)
+	return lipgloss.NewStyle().MaxWidth(m.width).Render(line)
+}
+
+// likelyIntended renders the sidebar hint for a typosquat suspect.
+func likelyIntended(sq commands.TyposquatMsg) string {
+	if sq.Suggestion == "" {
+		return ""
+	}
+	return typosquatWarning(sq)
+}
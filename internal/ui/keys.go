@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+
+	clist "github.com/fredrikmwold/npm-tui/internal/ui/components/list"
+)
+
+// reservedKeys are used by list navigation and link cycling, which handle them
+// before actions, and cannot be rebound. The other letters bubbles binds for
+// paging are removed from the list's KeyMap.
+var reservedKeys = map[string]string{
+	"j": "list navigation",
+	"k": "list navigation",
+	"q": "the list",
+	"?": "the list",
+	"/": "the list",
+	"[": "link cycling",
+	"]": "link cycling",
+}
+
+// keyActions maps the action names used in the config file to bindings.
+func keyActions(km *clist.KeyMap) map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"install":           &km.Install,
+		"install-dev":       &km.InstallDev,
+		"update":            &km.Update,
+		"changelog":         &km.Changelog,
+		"readme":            &km.Readme,
+		"chart-window":      &km.ChartWindow,
+		"chart-granularity": &km.Granularity,
+		"tarball":           &km.Tarball,
+		"mark":              &km.Mark,
+		"compare":           &km.Compare,
+		"theme":             &km.Theme,
+		"copy":              &km.Copy,
+		"open":              &km.Open,
+		"star":              &km.Star,
+		"watchlist":         &km.Watchlist,
+	}
+}
+
+// newKeyMap applies the config file's key overrides to the defaults. Each
+// override replaces all keys of an action; an empty list unbinds it. Keys
+// must be single characters, unreserved and bound to one action only.
+func newKeyMap(overrides map[string][]string) (clist.KeyMap, error) {
+	km := clist.DefaultKeyMap()
+	actions := keyActions(&km)
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		b, ok := actions[name]
+		if !ok {
+			valid := make([]string, 0, len(actions))
+			for a := range actions {
+				valid = append(valid, a)
+			}
+			sort.Strings(valid)
+			return km, fmt.Errorf("keys: unknown action %q (valid: %s)", name, strings.Join(valid, ", "))
+		}
+		keys := overrides[name]
+		for _, k := range keys {
+			if utf8.RuneCountInString(k) != 1 {
+				return km, fmt.Errorf("keys.%s: %q is not a single character", name, k)
+			}
+			if why, ok := reservedKeys[k]; ok {
+				return km, fmt.Errorf("keys.%s: %q is reserved for %s", name, k, why)
+			}
+		}
+		if len(keys) == 0 {
+			b.SetEnabled(false)
+			continue
+		}
+		b.SetKeys(keys...)
+		b.SetHelp(keys[0], b.Help().Desc)
+	}
+	// Reject keys bound to two actions, including clashes with defaults
+	owner := map[string]string{}
+	actionNames := make([]string, 0, len(actions))
+	for a := range actions {
+		actionNames = append(actionNames, a)
+	}
+	sort.Strings(actionNames)
+	for _, a := range actionNames {
+		b := actions[a]
+		if !b.Enabled() {
+			continue
+		}
+		for _, k := range b.Keys() {
+			if prev, ok := owner[k]; ok {
+				return km, fmt.Errorf("keys: %q is bound to both %s and %s", k, prev, a)
+			}
+			owner[k] = a
+		}
+	}
+	return km, nil
+}
@@ -0,0 +1,32 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewKeyMapRejectsCollisions(t *testing.T) {
+	tests := []struct {
+		overrides map[string][]string
+		wantErr   string
+	}{
+		{map[string][]string{"install": {"x"}}, ""},
+		{map[string][]string{"install": {}}, ""},
+		{map[string][]string{"install": {"j"}}, "reserved for list navigation"},
+		{map[string][]string{"copy": {"/"}}, "reserved for the list"},
+		{map[string][]string{"open": {"["}}, "reserved for link cycling"},
+		{map[string][]string{"star": {"]"}}, "reserved for link cycling"},
+		{map[string][]string{"install": {"xy"}}, "not a single character"},
+		{map[string][]string{"install": {"x"}, "copy": {"x"}}, "bound to both"},
+		{map[string][]string{"nope": {"x"}}, "unknown action"},
+	}
+	for _, tt := range tests {
+		_, err := newKeyMap(tt.overrides)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("newKeyMap(%v) = %v, want no error", tt.overrides, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("newKeyMap(%v) = %v, want error containing %q", tt.overrides, err, tt.wantErr)
+		}
+	}
+}
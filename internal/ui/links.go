@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"net/url"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/fredrikmwold/npm-tui/internal/commands"
+	"github.com/fredrikmwold/npm-tui/internal/ui/components"
+	clist "github.com/fredrikmwold/npm-tui/internal/ui/components/list"
+)
+
+// linkKeys maps sidebar link kinds to their key and name in the open and
+// copy menus.
+var linkKeys = map[string]struct{ key, what string }{
+	"repo": {"r", "repository"},
+	"home": {"h", "homepage"},
+	"npm":  {"p", "npm"},
+	"bugs": {"b", "issues"},
+}
+
+// issuesURL returns the issue tracker of a package: the bugs field, or the
+// issues page of a GitHub repository.
+func issuesURL(det clist.Details) string {
+	if det.Bugs != "" {
+		return det.Bugs
+	}
+	for _, l := range components.PackageLinks(det.Repository, "", "", "") {
+		if strings.HasPrefix(l.URL, "https://github.com/") && strings.Count(strings.TrimPrefix(l.URL, "https://github.com/"), "/") == 1 {
+			return l.URL + "/issues"
+		}
+	}
+	return ""
+}
+
+// linkTargets lists the links of the selected package.
+func (m *Model) linkTargets() []menuTarget {
+	det, ok := m.list.SelectedDetails()
+	if !ok {
+		return nil
+	}
+	var targets []menuTarget
+	for _, l := range components.PackageLinks(det.Repository, det.Homepage, det.NPMLink, issuesURL(det)) {
+		k := linkKeys[l.Kind]
+		targets = append(targets, menuTarget{key: k.key, what: k.what, text: l.URL})
+	}
+	return targets
+}
+
+// openLinkMenu asks which link to open with the next key.
+func (m *Model) openLinkMenu() {
+	m.openMenu("Open", m.linkTargets(), func(t menuTarget) tea.Cmd { return m.openURL(t.text) })
+}
+
+// openURL opens u with the configured launcher.
+func (m *Model) openURL(u string) tea.Cmd {
+	return commands.OpenURL(u, m.launch)
+}
+
+// SetLauncher replaces how URLs are opened, e.g. with a stub in tests.
+func (m *Model) SetLauncher(l commands.Launcher) { m.launch = l }
+
+// openToast describes the result of opening a URL for the status bar.
+func openToast(msg commands.OpenURLMsg) (components.ToastLevel, string) {
+	if msg.Err != nil {
+		return components.ToastError, "Could not open link: " + msg.Err.Error()
+	}
+	target := msg.URL
+	if u, err := url.Parse(msg.URL); err == nil && u.Host != "" {
+		target = u.Host
+	}
+	return components.ToastSuccess, "Opened " + target
+}
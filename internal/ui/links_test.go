@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/fredrikmwold/npm-tui/internal/commands"
+)
+
+func TestOpenLinkMenuUsesLauncher(t *testing.T) {
+	m, err := New(commands.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var opened []string
+	m.SetLauncher(func(u string) error {
+		opened = append(opened, u)
+		return nil
+	})
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	var obj commands.NpmSearchObject
+	obj.Package.Name = "react"
+	obj.Package.Version = "18.3.1"
+	obj.Package.Links.NPM = "https://www.npmjs.com/package/react"
+	obj.Package.Links.Repository = "https://github.com/facebook/react"
+	m.Update(commands.NpmSearchMsg{Query: "react", Result: commands.NpmSearchResult{Objects: []commands.NpmSearchObject{obj}}})
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"p", "https://www.npmjs.com/package/react"},
+		{"r", "https://github.com/facebook/react"},
+		{"b", "https://github.com/facebook/react/issues"},
+	}
+	for _, tt := range tests {
+		opened = nil
+		m.openLinkMenu()
+		if m.menu == nil {
+			t.Fatal("open menu did not open")
+		}
+		cmd := m.handleMenuKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(tt.key)})
+		if cmd == nil {
+			t.Fatalf("key %q opened nothing", tt.key)
+		}
+		msg, ok := cmd().(commands.OpenURLMsg)
+		if !ok || msg.Err != nil {
+			t.Fatalf("key %q: got %#v", tt.key, msg)
+		}
+		if len(opened) != 1 || opened[0] != tt.want {
+			t.Errorf("key %q opened %q, want %q", tt.key, opened, tt.want)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/fredrikmwold/npm-tui/internal/ui/theme"
+)
+
+// keyMenu is a one-line menu shown above the results, like the install
+// confirmation; the next key picks one of its targets or cancels.
+type keyMenu struct {
+	title   string
+	targets []menuTarget
+	run     func(menuTarget) tea.Cmd
+}
+
+// menuTarget is text a key menu acts on, such as a URL to open.
+type menuTarget struct {
+	// key picks the target in the menu
+	key  string
+	what string
+	text string
+}
+
+// openMenu shows a menu unless it has no targets.
+func (m *Model) openMenu(title string, targets []menuTarget, run func(menuTarget) tea.Cmd) {
+	if len(targets) == 0 {
+		return
+	}
+	m.menu = &keyMenu{title: title, targets: targets, run: run}
+	m.recomputeLayout()
+}
+
+// handleMenuKey runs the target picked by msg and closes the menu.
+func (m *Model) handleMenuKey(msg tea.KeyMsg) tea.Cmd {
+	menu := m.menu
+	m.menu = nil
+	m.recomputeLayout()
+	for _, t := range menu.targets {
+		if t.key == msg.String() {
+			return menu.run(t)
+		}
+	}
+	return nil
+}
+
+// menuView renders the open key menu.
+func (m *Model) menuView() string {
+	label := lipgloss.NewStyle().Foreground(theme.Crust).Background(theme.Mauve).Bold(true).Padding(0, 1)
+	keyStyle := lipgloss.NewStyle().Foreground(theme.Mauve).Bold(true)
+	hint := lipgloss.NewStyle().Foreground(theme.Subtext0)
+	opts := make([]string, 0, len(m.menu.targets))
+	for _, t := range m.menu.targets {
+		opts = append(opts, keyStyle.Render(t.key)+" "+hint.Render(t.what))
+	}
+	line := label.Render(m.menu.title) + " " + strings.Join(opts, hint.Render(" · ")) + hint.Render(" · any other key cancels")
+	return lipgloss.NewStyle().MaxWidth(m.width).Render(line)
+}
@@ -0,0 +1,217 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/fredrikmwold/npm-tui/internal/ui/components"
+)
+
+// Command palette action IDs.
+const (
+	paletteSearch       = "search"
+	paletteSaveQuery    = "save-query"
+	paletteSavedQueries = "saved-queries"
+	paletteProject      = "project"
+	paletteDetails      = "details"
+	paletteInstall      = "install"
+	paletteInstallDev   = "install-dev"
+	paletteUpdate       = "update"
+	paletteReadme       = "readme"
+	paletteChangelog    = "changelog"
+	paletteChartWindow  = "chart-window"
+	paletteGranularity  = "chart-granularity"
+	paletteTarball      = "tarball"
+	paletteMark         = "mark"
+	paletteCopy         = "copy:"
+	paletteOpen         = "open:"
+	paletteCompare      = "compare"
+	paletteStar         = "star"
+	paletteWatchlist    = "watchlist"
+	paletteTheme        = "theme"
+	paletteCorepack     = "corepack"
+	paletteQuit         = "quit"
+)
+
+// bindingKey returns the key shown for b in the palette, or "" when unbound.
+func bindingKey(b key.Binding) string {
+	if !b.Enabled() {
+		return ""
+	}
+	return b.Help().Key
+}
+
+// menuKey returns the key sequence choosing t in the menu opened by b.
+func menuKey(b key.Binding, t menuTarget) string {
+	if k := bindingKey(b); k != "" {
+		return k + " " + t.key
+	}
+	return ""
+}
+
+// runTarget runs the target picked by k.
+func runTarget(targets []menuTarget, k string, run func(menuTarget) tea.Cmd) tea.Cmd {
+	for _, t := range targets {
+		if t.key == k {
+			return run(t)
+		}
+	}
+	return nil
+}
+
+// paletteItems lists the actions that apply in the current context.
+func (m *Model) paletteItems() []components.PaletteItem {
+	var items []components.PaletteItem
+	add := func(id, title, key string) {
+		items = append(items, components.PaletteItem{ID: id, Title: title, Key: key})
+	}
+	next := m.themes[(m.themeIdx+1)%len(m.themes)].Name
+	// The comparison and the tarball browser only offer closing themselves
+	if m.compareOpen {
+		add(paletteCompare, "Close comparison", bindingKey(m.keys.Compare))
+		add(paletteTheme, "Switch theme to "+next, bindingKey(m.keys.Theme))
+		add(paletteQuit, "Quit", "ctrl+c")
+		return items
+	}
+	if m.tarballOpen {
+		add(paletteTarball, "Close file browser", bindingKey(m.keys.Tarball))
+		add(paletteTheme, "Switch theme to "+next, bindingKey(m.keys.Theme))
+		add(paletteQuit, "Quit", "ctrl+c")
+		return items
+	}
+
+	add(paletteSearch, "Search npm", "tab")
+	if q := strings.TrimSpace(m.input.Value()); q != "" {
+		add(paletteSaveQuery, "Save query "+q, "ctrl+s")
+	}
+	if len(m.history.Saved) > 0 {
+		add(paletteSavedQueries, "Saved queries", "ctrl+r")
+	}
+	if det, ok := m.list.SelectedDetails(); ok {
+		name := det.Name
+		if m.sideOpen {
+			add(paletteDetails, "Hide details", "enter")
+		} else {
+			add(paletteDetails, "Show details for "+name, "enter")
+		}
+		if m.installed[name] {
+			add(paletteUpdate, "Update "+name, bindingKey(m.keys.Update))
+		} else {
+			add(paletteInstall, "Install "+name, bindingKey(m.keys.Install))
+			add(paletteInstallDev, "Install "+name+" as dev dependency", bindingKey(m.keys.InstallDev))
+		}
+		if m.readmeOpen && !m.viewerChangelog {
+			add(paletteReadme, "Close README", bindingKey(m.keys.Readme))
+		} else {
+			add(paletteReadme, "Open README of "+name, bindingKey(m.keys.Readme))
+		}
+		if m.readmeOpen && m.viewerChangelog {
+			add(paletteChangelog, "Close changelog", bindingKey(m.keys.Changelog))
+		} else {
+			add(paletteChangelog, "Show changelog of "+name, bindingKey(m.keys.Changelog))
+		}
+		if m.sideOpen && !m.readmeOpen {
+			add(paletteChartWindow, fmt.Sprintf("Chart window: %s → %s", m.chartWindow, nextWindow(m.chartWindow)), bindingKey(m.keys.ChartWindow))
+			add(paletteGranularity, fmt.Sprintf("Chart granularity: %s → %s", m.chartGranularity, nextGranularity(m.chartGranularity)), bindingKey(m.keys.Granularity))
+		}
+		add(paletteTarball, "Browse files of "+name, bindingKey(m.keys.Tarball))
+		for _, t := range m.linkTargets() {
+			add(paletteOpen+t.key, "Open "+t.what+": "+t.text, menuKey(m.keys.Open, t))
+		}
+		for _, t := range m.copyTargets() {
+			add(paletteCopy+t.key, "Copy "+t.what+": "+t.text, menuKey(m.keys.Copy, t))
+		}
+		if m.watchlist.Has(name) {
+			add(paletteStar, "Unstar "+name, bindingKey(m.keys.Star))
+		} else {
+			add(paletteStar, "Star "+name+" (add to watchlist)", bindingKey(m.keys.Star))
+		}
+		if slices.Contains(m.list.Marked(), name) {
+			add(paletteMark, "Unmark "+name, bindingKey(m.keys.Mark))
+		} else {
+			add(paletteMark, "Mark "+name+" for comparison", bindingKey(m.keys.Mark))
+		}
+	}
+	if n := len(m.list.Marked()); n >= 2 {
+		add(paletteCompare, fmt.Sprintf("Compare %d marked packages", n), bindingKey(m.keys.Compare))
+	}
+	add(paletteProject, "Show project packages", "esc")
+	if len(m.watchlist.Packages) > 0 {
+		add(paletteWatchlist, "Show watchlist", bindingKey(m.keys.Watchlist))
+	}
+	add(paletteTheme, "Switch theme to "+next, bindingKey(m.keys.Theme))
+	if m.canUseCorepack() {
+		if m.corepack {
+			add(paletteCorepack, "Stop running "+string(m.project.PackageManager)+" through corepack", "")
+		} else {
+			add(paletteCorepack, fmt.Sprintf("Run %s@%s through corepack", m.project.PackageManager, m.project.Declared), "")
+		}
+	}
+	add(paletteQuit, "Quit", "ctrl+c")
+	return items
+}
+
+// runPaletteAction runs the action chosen in the command palette.
+func (m *Model) runPaletteAction(id string) tea.Cmd {
+	if name, ok := strings.CutPrefix(id, paletteQuery); ok {
+		return m.runSavedQuery(name)
+	}
+	if k, ok := strings.CutPrefix(id, paletteCopy); ok {
+		return runTarget(m.copyTargets(), k, copyTarget)
+	}
+	if k, ok := strings.CutPrefix(id, paletteOpen); ok {
+		return runTarget(m.linkTargets(), k, func(t menuTarget) tea.Cmd { return m.openURL(t.text) })
+	}
+	switch id {
+	case paletteSearch:
+		m.focus = focusInput
+		m.applyFocus()
+	case paletteSaveQuery:
+		m.focus = focusInput
+		m.applyFocus()
+		m.startNaming()
+	case paletteSavedQueries:
+		return m.openQueryPicker()
+	case paletteProject:
+		return m.showProject()
+	case paletteDetails:
+		if m.focus == focusInput {
+			m.focus = focusResults
+			m.applyFocus()
+		}
+		return m.toggleDetails()
+	case paletteInstall, paletteUpdate:
+		return m.installSelected(false)
+	case paletteInstallDev:
+		return m.installSelected(true)
+	case paletteReadme:
+		return m.toggleReadme()
+	case paletteChangelog:
+		return m.toggleChangelog()
+	case paletteChartWindow:
+		return m.cycleChart(true)
+	case paletteGranularity:
+		return m.cycleChart(false)
+	case paletteTarball:
+		return m.toggleTarball()
+	case paletteMark:
+		return m.toggleMark()
+	case paletteCompare:
+		return m.toggleCompare()
+	case paletteStar:
+		return m.toggleStar()
+	case paletteWatchlist:
+		return m.showWatchlist()
+	case paletteTheme:
+		m.cycleTheme()
+	case paletteCorepack:
+		return m.toggleCorepack()
+	case paletteQuit:
+		return tea.Quit
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/fredrikmwold/npm-tui/internal/commands"
+	"github.com/fredrikmwold/npm-tui/internal/ui/components"
+)
+
+// onlinePollInterval is how often the registry is pinged for the status bar.
+const onlinePollInterval = 30 * time.Second
+
+// checkOnline pings the registry after delay. Only the latest check keeps
+// polling, so an immediate recheck does not start a second poll loop.
+func (m *Model) checkOnline(delay time.Duration) tea.Cmd {
+	m.onlineReq++
+	return commands.CheckOnline(delay, m.onlineReq)
+}
+
+// toastLevels maps command toast levels to the status bar's.
+var toastLevels = map[commands.ToastLevel]components.ToastLevel{
+	commands.ToastSuccess: components.ToastSuccess,
+	commands.ToastWarning: components.ToastWarning,
+	commands.ToastError:   components.ToastError,
+}
+
+// toast queues a notification in the status bar.
+func (m *Model) toast(level components.ToastLevel, text string) tea.Cmd {
+	return m.status.Push(level, text)
+}
+
+// registryHost is the registry as shown in the status bar.
+func registryHost() string {
+	if u, err := url.Parse(commands.RegistryURL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return commands.RegistryURL
+}
+
+// showProjectStatus shows the detected project in the status bar, with the
+// home directory abbreviated to ~.
+func (m *Model) showProjectStatus() {
+	msg := m.project
+	dir := msg.Dir
+	if home, err := os.UserHomeDir(); err == nil && home != "" {
+		if dir == home {
+			dir = "~"
+		} else if strings.HasPrefix(dir, home+string(filepath.Separator)) {
+			dir = "~" + dir[len(home):]
+		}
+	}
+	pm := commands.Toolchain{PM: msg.PackageManager, Version: msg.Version, Corepack: m.corepack}.String()
+	switch {
+	case !msg.Mismatch() || m.corepack:
+	case msg.Installed == "":
+		pm += " ⚠ not installed"
+	default:
+		pm += " ⚠ installed " + msg.Installed
+	}
+	if msg.Lockfile == "" {
+		pm += " (no lockfile)"
+	}
+	m.status.SetProject(pm, dir)
+}
+
+// installToast describes a finished install; failures include the last line
+// of the package manager's output.
+func installToast(msg commands.NpmInstallMsg, update bool) (components.ToastLevel, string) {
+	if msg.Err != nil {
+		text := "Install of " + msg.Package + " failed: " + msg.Err.Error()
+		lines := strings.Split(strings.TrimSpace(msg.Output), "\n")
+		if last := strings.TrimSpace(lines[len(lines)-1]); last != "" {
+			text += " · " + last
+		}
+		return components.ToastError, text
+	}
+	if update {
+		return components.ToastSuccess, "Updated " + msg.Package
+	}
+	return components.ToastSuccess, "Installed " + msg.Package
+}
@@ -0,0 +1,43 @@
+package theme
+
+import (
+	"github.com/charmbracelet/glamour/ansi"
+	"github.com/charmbracelet/glamour/styles"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// GlamourStyle derives a Markdown style from the active theme, starting from
+// glamour's dark or light style for layout and replacing its colors.
+func GlamourStyle() ansi.StyleConfig {
+	t := current
+	s := styles.DarkStyleConfig
+	if t.Light {
+		s = styles.LightStyleConfig
+	}
+	c := func(v lipgloss.Color) *string {
+		str := string(v)
+		return &str
+	}
+	s.Document.Color = c(t.Text)
+	s.BlockQuote.Color = c(t.Subtext0)
+	s.Heading.Color = c(t.Mauve)
+	s.H1.Color = c(t.Crust)
+	s.H1.BackgroundColor = c(t.Lavender)
+	s.H6.Color = c(t.Subtext0)
+	s.HorizontalRule.Color = c(t.Surface2)
+	s.Link.Color = c(t.Blue)
+	s.LinkText.Color = c(t.Sky)
+	s.Image.Color = c(t.Peach)
+	s.ImageText.Color = c(t.Subtext0)
+	s.Code.Color = c(t.Peach)
+	s.Code.BackgroundColor = c(t.Surface0)
+	s.CodeBlock.Color = c(t.Text)
+	// A named chroma style can change at runtime; glamour registers the
+	// inline Chroma config only once per process
+	s.CodeBlock.Chroma = nil
+	s.CodeBlock.Theme = t.Chroma
+	s.Item.Color = c(t.Text)
+	s.Enumeration.Color = c(t.Text)
+	s.Table.Color = c(t.Text)
+	return s
+}
@@ -1,29 +1,28 @@
 package theme
 
-import "github.com/charmbracelet/lipgloss"
-
-// Catppuccin Mocha palette (subset)
+// Active palette, initially the Catppuccin Mocha based Dark theme. Components
+// read these when rendering; Apply replaces them.
 var (
 	// Core
-	Base     = lipgloss.Color("#1e1e2e")
-	Mantle   = lipgloss.Color("#181825")
-	Crust    = lipgloss.Color("#11111b")
-	Text     = lipgloss.Color("#cdd6f4")
-	Subtext0 = lipgloss.Color("#a6adc8")
-	Surface0 = lipgloss.Color("#313244")
-	Surface1 = lipgloss.Color("#45475a")
-	Surface2 = lipgloss.Color("#585b70")
+	Base     = Dark.Base
+	Mantle   = Dark.Mantle
+	Crust    = Dark.Crust
+	Text     = Dark.Text
+	Subtext0 = Dark.Subtext0
+	Surface0 = Dark.Surface0
+	Surface1 = Dark.Surface1
+	Surface2 = Dark.Surface2
 
 	// Accents
-	Mauve    = lipgloss.Color("#cba6f7")
-	Lavender = lipgloss.Color("#b4befe")
-	Blue     = lipgloss.Color("#89b4fa")
-	Green    = lipgloss.Color("#a6e3a1")
-	Peach    = lipgloss.Color("#fab387")
-	Red      = lipgloss.Color("#f38ba8")
+	Mauve    = Dark.Mauve
+	Lavender = Dark.Lavender
+	Blue     = Dark.Blue
+	Green    = Dark.Green
+	Peach    = Dark.Peach
+	Red      = Dark.Red
 	// Additional accents to reduce clashes
-	Sky    = lipgloss.Color("#89dceb")
-	Yellow = lipgloss.Color("#f9e2af")
+	Sky    = Dark.Sky
+	Yellow = Dark.Yellow
 )
 
 // Convenience
@@ -31,3 +30,6 @@ var (
 	BorderUnfocused = Surface2
 	BorderFocused   = Mauve
 )
+
+// current is the theme last passed to Apply.
+var current = Dark
@@ -0,0 +1,141 @@
+package theme
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme is a complete palette plus the code highlighting style that matches it.
+type Theme struct {
+	Name string
+	// Light is set for themes meant for light terminal backgrounds
+	Light bool
+	// Chroma names the syntax highlighting style for code
+	Chroma string
+
+	Base, Mantle, Crust                 lipgloss.Color
+	Text, Subtext0                      lipgloss.Color
+	Surface0, Surface1, Surface2        lipgloss.Color
+	Mauve, Lavender, Blue, Green, Peach lipgloss.Color
+	Red, Sky, Yellow                    lipgloss.Color
+}
+
+// Built-in themes.
+var (
+	// Dark is Catppuccin Mocha.
+	Dark = Theme{
+		Name: "dark", Chroma: "catppuccin-mocha",
+		Base: "#1e1e2e", Mantle: "#181825", Crust: "#11111b",
+		Text: "#cdd6f4", Subtext0: "#a6adc8",
+		Surface0: "#313244", Surface1: "#45475a", Surface2: "#585b70",
+		Mauve: "#cba6f7", Lavender: "#b4befe", Blue: "#89b4fa", Green: "#a6e3a1", Peach: "#fab387",
+		Red: "#f38ba8", Sky: "#89dceb", Yellow: "#f9e2af",
+	}
+	// Light is Catppuccin Latte.
+	Light = Theme{
+		Name: "light", Light: true, Chroma: "catppuccin-latte",
+		Base: "#eff1f5", Mantle: "#e6e9ef", Crust: "#dce0e8",
+		Text: "#4c4f69", Subtext0: "#6c6f85",
+		Surface0: "#ccd0da", Surface1: "#bcc0cc", Surface2: "#9ca0b0",
+		Mauve: "#8839ef", Lavender: "#7287fd", Blue: "#1e66f5", Green: "#40a02b", Peach: "#fe640b",
+		Red: "#d20f39", Sky: "#04a5e5", Yellow: "#df8e1d",
+	}
+	// HighContrast uses pure black and white with saturated accents.
+	HighContrast = Theme{
+		Name: "high-contrast", Chroma: "github-dark",
+		Base: "#000000", Mantle: "#000000", Crust: "#000000",
+		Text: "#ffffff", Subtext0: "#e0e0e0",
+		Surface0: "#303030", Surface1: "#505050", Surface2: "#b0b0b0",
+		Mauve: "#ff5fff", Lavender: "#afafff", Blue: "#5fafff", Green: "#5fff5f", Peach: "#ffaf00",
+		Red: "#ff5f5f", Sky: "#5fffff", Yellow: "#ffff00",
+	}
+)
+
+// Builtins lists the built-in themes in switcher order.
+func Builtins() []Theme { return []Theme{Dark, Light, HighContrast} }
+
+// Apply makes t the active palette.
+func Apply(t Theme) {
+	current = t
+	Base, Mantle, Crust = t.Base, t.Mantle, t.Crust
+	Text, Subtext0 = t.Text, t.Subtext0
+	Surface0, Surface1, Surface2 = t.Surface0, t.Surface1, t.Surface2
+	Mauve, Lavender, Blue, Green, Peach = t.Mauve, t.Lavender, t.Blue, t.Green, t.Peach
+	Red, Sky, Yellow = t.Red, t.Sky, t.Yellow
+	BorderUnfocused = Surface2
+	BorderFocused = Mauve
+}
+
+// Current returns the active theme.
+func Current() Theme { return current }
+
+var hexColor = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// Custom builds a user theme named name from the built-in theme called base
+// ("dark" when empty) with colors overriding palette entries by lowercase
+// name, e.g. {"text": "#ffffff"}. Colors are hex or ANSI 0-255.
+func Custom(name, base string, colors map[string]string) (Theme, error) {
+	t := Dark
+	if base != "" {
+		b, ok := Builtin(base)
+		if !ok {
+			return t, fmt.Errorf("base %q is not one of dark, light, high-contrast", base)
+		}
+		t = b
+	}
+	t.Name = name
+	slots := t.slots()
+	keys := make([]string, 0, len(colors))
+	for k := range colors {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		slot, ok := slots[strings.ToLower(k)]
+		if !ok {
+			return t, fmt.Errorf("unknown color %q", k)
+		}
+		v := colors[k]
+		if !hexColor.MatchString(v) && !isANSIColor(v) {
+			return t, fmt.Errorf("%s: %q is not a #rrggbb or 0-255 color", k, v)
+		}
+		*slot = lipgloss.Color(v)
+	}
+	return t, nil
+}
+
+// Builtin returns the built-in theme called name.
+func Builtin(name string) (Theme, bool) {
+	for _, t := range Builtins() {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Theme{}, false
+}
+
+// slots maps lowercase palette names to the fields of t.
+func (t *Theme) slots() map[string]*lipgloss.Color {
+	return map[string]*lipgloss.Color{
+		"base": &t.Base, "mantle": &t.Mantle, "crust": &t.Crust,
+		"text": &t.Text, "subtext0": &t.Subtext0,
+		"surface0": &t.Surface0, "surface1": &t.Surface1, "surface2": &t.Surface2,
+		"mauve": &t.Mauve, "lavender": &t.Lavender, "blue": &t.Blue, "green": &t.Green, "peach": &t.Peach,
+		"red": &t.Red, "sky": &t.Sky, "yellow": &t.Yellow,
+	}
+}
+
+func isANSIColor(s string) bool {
+	n := 0
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+		n = n*10 + int(r-'0')
+	}
+	return s != "" && len(s) <= 3 && n <= 255
+}
@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/fredrikmwold/npm-tui/internal/commands"
+	"github.com/fredrikmwold/npm-tui/internal/ui/theme"
+)
+
+// newThemes builds the switcher's themes, the built-ins followed by the
+// config file's themes by name, and returns the index of the configured
+// initial theme.
+func newThemes(cfg commands.Config) ([]theme.Theme, int, error) {
+	themes := theme.Builtins()
+	names := make([]string, 0, len(cfg.Themes))
+	for name := range cfg.Themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := theme.Builtin(name); ok {
+			return nil, 0, fmt.Errorf("themes.%s: name is taken by a built-in theme", name)
+		}
+		tc := cfg.Themes[name]
+		t, err := theme.Custom(name, tc.Base, tc.Colors)
+		if err != nil {
+			return nil, 0, fmt.Errorf("themes.%s: %w", name, err)
+		}
+		themes = append(themes, t)
+	}
+	if cfg.Theme == "" {
+		return themes, 0, nil
+	}
+	for i, t := range themes {
+		if t.Name == cfg.Theme {
+			return themes, i, nil
+		}
+	}
+	return nil, 0, fmt.Errorf("theme: unknown theme %q", cfg.Theme)
+}
+
+// cycleTheme switches to the next theme and restyles every component.
+func (m *Model) cycleTheme() {
+	if len(m.themes) == 0 {
+		return
+	}
+	m.themeIdx = (m.themeIdx + 1) % len(m.themes)
+	theme.Apply(m.themes[m.themeIdx])
+	m.input.ApplyTheme()
+	m.list.ApplyTheme()
+	m.side.ApplyTheme()
+	m.readme.ApplyTheme()
+	m.compare.ApplyTheme()
+	m.tarball.ApplyTheme()
+	m.palette.ApplyTheme()
+	m.spinner.Style = m.spinner.Style.Foreground(theme.Mauve)
+	m.applyFocus()
+	m.recomputeLayout()
+}
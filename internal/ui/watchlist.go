@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/fredrikmwold/npm-tui/internal/commands"
+	"github.com/fredrikmwold/npm-tui/internal/ui/components"
+)
+
+// setWatchlist replaces the watchlist and the stars in the list.
+func (m *Model) setWatchlist(w commands.Watchlist) {
+	m.watchlist = w
+	starred := make(map[string]bool, len(w.Packages))
+	for _, p := range w.Packages {
+		starred[p.Name] = true
+	}
+	m.list.SetStarred(starred)
+}
+
+// loadWatchlist takes the watchlist read from disk, keeping packages starred
+// while it was loading, and allows saving from now on.
+func (m *Model) loadWatchlist(w commands.Watchlist) tea.Cmd {
+	early := m.watchlist
+	for _, p := range early.Packages {
+		if !w.Has(p.Name) {
+			w.Toggle(p.Name, p.Seen)
+		}
+	}
+	m.watchlistLoaded = true
+	m.setWatchlist(w)
+	if len(early.Packages) == 0 {
+		return nil
+	}
+	return m.saveWatchlist(w)
+}
+
+// saveWatchlist writes w once the watchlist file was read.
+func (m *Model) saveWatchlist(w commands.Watchlist) tea.Cmd {
+	if !m.watchlistLoaded {
+		return nil
+	}
+	return commands.SaveWatchlist(w)
+}
+
+// toggleStar adds the selected package to the watchlist, or removes it.
+func (m *Model) toggleStar() tea.Cmd {
+	det, ok := m.list.SelectedDetails()
+	if !ok || det.Name == "" {
+		return nil
+	}
+	w := m.watchlist
+	starred := w.Toggle(det.Name, det.Latest)
+	m.setWatchlist(w)
+	text := "Starred " + det.Name
+	if !starred {
+		delete(m.watchNew, det.Name)
+		m.list.SetNewSince(m.watchNew)
+		text = "Unstarred " + det.Name
+	}
+	return tea.Batch(m.saveWatchlist(w), m.toast(components.ToastSuccess, text))
+}
+
+// showWatchlist loads the watched packages into the list.
+func (m *Model) showWatchlist() tea.Cmd {
+	if len(m.watchlist.Packages) == 0 {
+		return m.toast(components.ToastWarning, "The watchlist is empty; star packages with "+bindingKey(m.keys.Star))
+	}
+	m.focus = focusResults
+	m.sideOpen = false
+	m.readmeOpen = false
+	m.readmeLoading = false
+	m.applyFocus()
+	m.loading = true
+	m.list.SetTitle("Loading watchlist…")
+	m.list.SetPlaceholder("Loading watchlist…")
+	m.recomputeLayout()
+	return commands.LoadWatchedPackages(m.watchlist.Names())
+}
+
+// noteNewVersions badges watched packages whose latest version, by name, is
+// newer than the one last seen. When the watchlist itself is shown, the
+// latest versions count as seen from now on; the badges stay for the session.
+func (m *Model) noteNewVersions(latestByName map[string]string, watchlist bool) tea.Cmd {
+	w := m.watchlist
+	fresh := 0
+	for name, latest := range latestByName {
+		if seen, ok := w.NewSince(name, latest); ok {
+			fresh++
+			m.watchNew[name] = seen
+		}
+		if watchlist {
+			w.MarkSeen(name, latest)
+		}
+	}
+	m.list.SetNewSince(m.watchNew)
+	if !watchlist {
+		return nil
+	}
+	m.setWatchlist(w)
+	cmds := []tea.Cmd{m.saveWatchlist(w)}
+	switch {
+	case fresh == 1:
+		cmds = append(cmds, m.toast(components.ToastSuccess, "1 watched package has a new version"))
+	case fresh > 1:
+		cmds = append(cmds, m.toast(components.ToastSuccess, fmt.Sprintf("%d watched packages have new versions", fresh)))
+	}
+	return tea.Batch(cmds...)
+}